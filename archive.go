@@ -0,0 +1,91 @@
+package dotenv
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"strings"
+	"testing/fstest"
+)
+
+// WithArchiveSource configures [Loader] to use zip or tar archive archivePath,
+// like a deployment bundle, as its filesystem, see [Loader.WithFS]. So tools,
+// which inspect build artifacts, can resolve the environment an artifact would
+// load:
+//
+//	scoped, err := dotenv.New().WithArchiveSource("bundle.tar.gz").
+//		For("/srv/app")
+//
+// Format of archive is detected by extension of archivePath: ".zip", ".tar",
+// ".tar.gz" or ".tgz". The archive is read into memory once, by this call. If
+// it can't be read, [Loader.Validate] and [Loader.Load] return the error.
+func (self *Loader) WithArchiveSource(archivePath string) *Loader {
+	fsys, err := openArchive(archivePath)
+	if err != nil {
+		self.archiveErr = fmt.Errorf("archive source %v: %w", archivePath, err)
+		return self
+	}
+	self.archiveErr = nil
+	return self.WithFS(fsys)
+}
+
+// openArchive reads archive fname and returns its content as [fs.FS].
+func openArchive(fname string) (fs.FS, error) {
+	b, err := os.ReadFile(fname)
+	if err != nil {
+		return nil, err //nolint:wrapcheck // caller wraps it
+	}
+
+	switch name := strings.ToLower(fname); {
+	case strings.HasSuffix(name, ".zip"):
+		r, err := zip.NewReader(bytes.NewReader(b), int64(len(b)))
+		if err != nil {
+			return nil, err //nolint:wrapcheck // caller wraps it
+		}
+		return r, nil
+	case strings.HasSuffix(name, ".tar"):
+		return tarFS(bytes.NewReader(b))
+	case strings.HasSuffix(name, ".tar.gz"), strings.HasSuffix(name, ".tgz"):
+		r, err := gzip.NewReader(bytes.NewReader(b))
+		if err != nil {
+			return nil, err //nolint:wrapcheck // caller wraps it
+		}
+		return tarFS(r)
+	}
+	return nil, errors.New("unknown format of archive")
+}
+
+// tarFS reads tar archive from r and returns its regular files as [fs.FS].
+func tarFS(r io.Reader) (fs.FS, error) {
+	fsys := make(fstest.MapFS)
+	tr := tar.NewReader(r)
+	for {
+		hdr, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			return fsys, nil
+		} else if err != nil {
+			return nil, err //nolint:wrapcheck // caller wraps it
+		} else if hdr.Typeflag != tar.TypeReg {
+			continue
+		}
+
+		name := strings.TrimPrefix(path.Clean("/"+hdr.Name), "/")
+		if name == "" {
+			continue
+		}
+		b, err := io.ReadAll(tr)
+		if err != nil {
+			return nil, err //nolint:wrapcheck // caller wraps it
+		}
+		fsys[name] = &fstest.MapFile{
+			Data: b, Mode: hdr.FileInfo().Mode(), ModTime: hdr.ModTime,
+		}
+	}
+}
@@ -0,0 +1,108 @@
+package dotenv
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var archiveFiles = map[string]string{
+	".env":                "TEST_VAR1=root\nTEST_VAR2=root\n",
+	"srv/app/.env":        "TEST_VAR1=app\n",
+	"srv/app/.env.local":  "TEST_VAR2=app local\n",
+	"srv/app/sub/README":  "readme\n",
+	"srv/other/.env.test": "TEST_VAR1=other\n",
+}
+
+func TestLoader_WithArchiveSource(t *testing.T) {
+	dir := t.TempDir()
+	archives := map[string][]byte{
+		"bundle.zip":    zipArchive(t),
+		"bundle.tar":    tarArchive(t),
+		"bundle.tar.gz": gzipData(t, tarArchive(t)),
+		"bundle.tgz":    gzipData(t, tarArchive(t)),
+	}
+
+	for name, b := range archives {
+		t.Run(name, func(t *testing.T) {
+			restoreEnvVars(t)
+			fname := filepath.Join(dir, name)
+			require.NoError(t, os.WriteFile(fname, b, 0o600))
+
+			env := New()
+			assert.Same(t, env, env.WithArchiveSource(fname))
+			scoped, err := env.For("/srv/app/sub")
+			require.NoError(t, err)
+			assert.Equal(t, "app", scoped.Getenv(allEnvVars[0]))
+			assert.Equal(t, "app local", scoped.Getenv(allEnvVars[1]))
+			assert.Equal(t, []string{"/srv/app/.env.local", "/srv/app/.env"},
+				scoped.Result().Files)
+			for _, name := range allEnvVars {
+				assert.Empty(t, os.Getenv(name), name)
+			}
+		})
+	}
+}
+
+func TestLoader_WithArchiveSource_errors(t *testing.T) {
+	dir := t.TempDir()
+	unknown := filepath.Join(dir, "bundle.rar")
+	require.NoError(t, os.WriteFile(unknown, nil, 0o600))
+	broken := filepath.Join(dir, "bundle.zip")
+	require.NoError(t, os.WriteFile(broken, []byte("not a zip"), 0o600))
+
+	for _, fname := range []string{
+		filepath.Join(dir, "not-exists.zip"), unknown, broken,
+	} {
+		env := New().WithArchiveSource(fname)
+		require.ErrorIs(t, env.Validate(), ErrInvalidConfig)
+		require.ErrorContains(t, env.Load(), "archive source "+fname)
+	}
+}
+
+func zipArchive(t *testing.T) []byte {
+	var buf bytes.Buffer
+	w := zip.NewWriter(&buf)
+	for name, data := range archiveFiles {
+		f, err := w.Create(name)
+		require.NoError(t, err)
+		_, err = io.WriteString(f, data)
+		require.NoError(t, err)
+	}
+	require.NoError(t, w.Close())
+	return buf.Bytes()
+}
+
+func tarArchive(t *testing.T) []byte {
+	var buf bytes.Buffer
+	w := tar.NewWriter(&buf)
+	require.NoError(t, w.WriteHeader(&tar.Header{
+		Name: "srv/", Typeflag: tar.TypeDir, Mode: 0o755,
+	}))
+	for name, data := range archiveFiles {
+		require.NoError(t, w.WriteHeader(&tar.Header{
+			Name: name, Typeflag: tar.TypeReg, Mode: 0o644, Size: int64(len(data)),
+		}))
+		_, err := io.WriteString(w, data)
+		require.NoError(t, err)
+	}
+	require.NoError(t, w.Close())
+	return buf.Bytes()
+}
+
+func gzipData(t *testing.T, b []byte) []byte {
+	var buf bytes.Buffer
+	w := gzip.NewWriter(&buf)
+	_, err := w.Write(b)
+	require.NoError(t, err)
+	require.NoError(t, w.Close())
+	return buf.Bytes()
+}
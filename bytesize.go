@@ -0,0 +1,60 @@
+package dotenv
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// ByteSize is a size in bytes, which [Decode] parses from strings like
+// "512MiB", "1.5GB" or "1024". Decimal units (KB, MB, GB, TB, PB) are powers
+// of 1000 and binary units (KiB, MiB, GiB, TiB, PiB) are powers of 1024. Units
+// are case insensitive.
+type ByteSize uint64
+
+var byteSizeUnits = map[string]float64{
+	"":    1,
+	"b":   1,
+	"k":   1e3,
+	"kb":  1e3,
+	"m":   1e6,
+	"mb":  1e6,
+	"g":   1e9,
+	"gb":  1e9,
+	"t":   1e12,
+	"tb":  1e12,
+	"p":   1e15,
+	"pb":  1e15,
+	"kib": 1 << 10,
+	"mib": 1 << 20,
+	"gib": 1 << 30,
+	"tib": 1 << 40,
+	"pib": 1 << 50,
+}
+
+// UnmarshalText implements [encoding.TextUnmarshaler].
+func (self *ByteSize) UnmarshalText(text []byte) error {
+	s := strings.TrimSpace(string(text))
+	i := strings.IndexFunc(s, func(r rune) bool {
+		return (r < '0' || r > '9') && r != '.'
+	})
+	if i < 0 {
+		i = len(s)
+	}
+
+	num, unit := s[:i], strings.ToLower(strings.TrimSpace(s[i:]))
+	mult, ok := byteSizeUnits[unit]
+	if !ok {
+		return fmt.Errorf("unknown unit of size %q", s)
+	}
+
+	f, err := strconv.ParseFloat(num, 64)
+	if err != nil {
+		return fmt.Errorf("parse size %q: %w", s, err)
+	} else if f *= mult; f >= math.MaxUint64 {
+		return fmt.Errorf("size %q is too large", s)
+	}
+	*self = ByteSize(f)
+	return nil
+}
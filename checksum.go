@@ -0,0 +1,50 @@
+package dotenv
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strings"
+)
+
+// ErrChecksum returned by [Loader.Load] if checksum of a .env file doesn't
+// match its checksum, configured by [Loader.WithPinnedChecksums].
+var ErrChecksum = errors.New("checksum mismatch")
+
+// WithPinnedChecksums configures [Loader.Load] to verify SHA-256 checksum of
+// every loaded .env file against sums, which is a map of hex encoded checksums
+// by base name of .env files, like:
+//
+//	env := dotenv.New().WithPinnedChecksums(map[string]string{
+//		".env": "2c26b46b68ffc68ff99b453c1d30413413422d706483bfa0f98a5e886266e7ae",
+//	})
+//
+// Checksums can be baked at build time and it defends against tampering of
+// mounted config. Load returns an error wrapping [ErrChecksum], if a checksum
+// doesn't match or a loaded .env file has no pinned checksum.
+func (self *Loader) WithPinnedChecksums(sums map[string]string) *Loader {
+	self.checksums = sums
+	return self
+}
+
+// verifyChecksum returns an error if checksum of b, read from fname, doesn't
+// match pinned checksum.
+func (self *Loader) verifyChecksum(fname string, b []byte) error {
+	if self.checksums == nil {
+		return nil
+	}
+
+	want, ok := self.checksums[filepath.Base(fname)]
+	if !ok {
+		return fmt.Errorf("%w: %v has no pinned checksum", ErrChecksum, fname)
+	}
+
+	sum := sha256.Sum256(b)
+	if got := hex.EncodeToString(sum[:]); !strings.EqualFold(got, want) {
+		return fmt.Errorf("%w: %v has sha256 %v, expected %v",
+			ErrChecksum, fname, got, want)
+	}
+	return nil
+}
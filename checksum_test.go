@@ -0,0 +1,72 @@
+package dotenv
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoader_WithPinnedChecksums(t *testing.T) {
+	changeDir(t, "testdata/g")
+
+	sums := make(map[string]string)
+	for _, fname := range []string{".env", ".env.local"} {
+		b, err := os.ReadFile(fname)
+		require.NoError(t, err)
+		sum := sha256.Sum256(b)
+		sums[fname] = hex.EncodeToString(sum[:])
+	}
+
+	tests := []struct {
+		name    string
+		sums    map[string]string
+		wantErr string
+	}{
+		{
+			name: "verified",
+			sums: sums,
+		},
+		{
+			name: "upper case",
+			sums: map[string]string{
+				".env":       strings.ToUpper(sums[".env"]),
+				".env.local": sums[".env.local"],
+			},
+		},
+		{
+			name: "mismatch",
+			sums: map[string]string{
+				".env":       sums[".env.local"],
+				".env.local": sums[".env.local"],
+			},
+			wantErr: ".env has sha256 " + sums[".env"],
+		},
+		{
+			name:    "not pinned",
+			sums:    map[string]string{".env": sums[".env"]},
+			wantErr: ".env.local has no pinned checksum",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			restoreEnvVars(t)
+			env := New()
+			assert.Same(t, env, env.WithPinnedChecksums(tt.sums))
+
+			err := env.Load()
+			if tt.wantErr == "" {
+				require.NoError(t, err)
+				assert.Equal(t, "local", os.Getenv(allEnvVars[0]))
+				return
+			}
+			require.ErrorIs(t, err, ErrChecksum)
+			assert.ErrorContains(t, err, tt.wantErr)
+		})
+	}
+}
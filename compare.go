@@ -0,0 +1,136 @@
+package dotenv
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+)
+
+// DiffKind is a kind of change of an env var, see [KeyDiff].
+type DiffKind int
+
+const (
+	// DiffAdded means env var is defined by second configuration only.
+	DiffAdded DiffKind = iota
+
+	// DiffRemoved means env var is defined by first configuration only.
+	DiffRemoved
+
+	// DiffChanged means env var is defined by both configurations with
+	// different values.
+	DiffChanged
+)
+
+func (self DiffKind) String() string {
+	switch self {
+	case DiffAdded:
+		return "added"
+	case DiffRemoved:
+		return "removed"
+	case DiffChanged:
+		return "changed"
+	}
+	return fmt.Sprintf("DiffKind(%d)", int(self))
+}
+
+// KeyDiff describes a change of an env var.
+type KeyDiff struct {
+	// Key is a name of env var.
+	Key string
+
+	// Kind is a kind of change.
+	Kind DiffKind
+
+	// Old is a value of first configuration, it's empty for [DiffAdded].
+	Old string
+
+	// New is a value of second configuration, it's empty for [DiffRemoved].
+	New string
+}
+
+// Diff is a key-level difference between two configurations, returned by
+// [Compare].
+type Diff struct {
+	// Keys contains changed env vars, sorted by key.
+	Keys []KeyDiff
+}
+
+// Empty returns true if configurations define the same env vars with the same
+// values.
+func (self *Diff) Empty() bool { return len(self.Keys) == 0 }
+
+// Compare resolves configurations of a and b, like [Loader.Load] does, but
+// without applying them to the process environment, and returns difference of
+// values of env vars, defined by .env files. Values of the process
+// environment, including env vars applied by last Load of a or b, are
+// ignored, so only .env files are compared. For instance, what changes if
+// environment switched to staging:
+//
+//	diff, err := dotenv.Compare(dotenv.New().WithEnvSuffix("dev"),
+//		dotenv.New().WithEnvSuffix("staging"))
+//	if err != nil {
+//		return err
+//	}
+//	for _, d := range diff.Keys {
+//		fmt.Println(d.Kind, d.Key)
+//	}
+//
+// It uses copies of a and b configuration, like [Loader.For] does, so their
+// [Loader.Result] doesn't change. Values are as is, so diff can contain
+// secrets.
+func Compare(a, b *Loader) (*Diff, error) {
+	oldVars, err := a.readFiles()
+	if err != nil {
+		return nil, fmt.Errorf("resolve first configuration: %w", err)
+	}
+
+	newVars, err := b.readFiles()
+	if err != nil {
+		return nil, fmt.Errorf("resolve second configuration: %w", err)
+	}
+
+	return diffVars(oldVars, newVars), nil
+}
+
+// readFiles returns env vars, defined by .env files of a copy of this
+// [Loader], regardless of the process environment.
+func (self *Loader) readFiles() (map[string]string, error) {
+	l := *self
+	l.noSetenv, l.override = true, true
+	l.vars, l.lastDir = nil, ""
+	l.result, l.recording, l.trace = nil, nil, nil
+	if err := l.Load(); err != nil {
+		return nil, err
+	}
+	return l.loaded, nil
+}
+
+// diffVars returns difference between oldVars and newVars.
+func diffVars(oldVars, newVars map[string]string) *Diff {
+	keys := slices.Collect(maps.Keys(oldVars))
+	for k := range newVars {
+		if _, ok := oldVars[k]; !ok {
+			keys = append(keys, k)
+		}
+	}
+	slices.Sort(keys)
+
+	diff := &Diff{}
+	for _, k := range keys {
+		oldValue, inOld := oldVars[k]
+		newValue, inNew := newVars[k]
+		switch {
+		case !inOld:
+			diff.Keys = append(diff.Keys,
+				KeyDiff{Key: k, Kind: DiffAdded, New: newValue})
+		case !inNew:
+			diff.Keys = append(diff.Keys,
+				KeyDiff{Key: k, Kind: DiffRemoved, Old: oldValue})
+		case oldValue != newValue:
+			diff.Keys = append(diff.Keys, KeyDiff{
+				Key: k, Kind: DiffChanged, Old: oldValue, New: newValue,
+			})
+		}
+	}
+	return diff
+}
@@ -0,0 +1,92 @@
+package dotenv
+
+import (
+	"errors"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCompare(t *testing.T) {
+	changeDir(t, "testdata/g")
+	restoreEnvVars(t)
+
+	diff, err := Compare(New(), New().WithEnvSuffix("test"))
+	require.NoError(t, err)
+	assert.Equal(t, []KeyDiff{
+		{Key: allEnvVars[1], Kind: DiffChanged, Old: "second", New: "test"},
+	}, diff.Keys)
+	assert.False(t, diff.Empty())
+	for _, name := range allEnvVars {
+		assert.Empty(t, os.Getenv(name), name)
+	}
+
+	diff, err = Compare(New(), New())
+	require.NoError(t, err)
+	assert.True(t, diff.Empty())
+}
+
+func TestCompare_processEnv(t *testing.T) {
+	changeDir(t, "testdata/g")
+	restoreEnvVars(t)
+	t.Setenv(allEnvVars[1], "from env")
+
+	diff, err := Compare(New(), New().WithEnvSuffix("test"))
+	require.NoError(t, err)
+	assert.Equal(t, []KeyDiff{
+		{Key: allEnvVars[1], Kind: DiffChanged, Old: "second", New: "test"},
+	}, diff.Keys)
+	assert.Equal(t, "from env", os.Getenv(allEnvVars[1]))
+
+	applied := New()
+	require.NoError(t, applied.Load())
+	diff, err = Compare(applied, New().WithEnvSuffix("test"))
+	require.NoError(t, err)
+	assert.Equal(t, []KeyDiff{
+		{Key: allEnvVars[1], Kind: DiffChanged, Old: "second", New: "test"},
+	}, diff.Keys)
+}
+
+func TestCompare_addedRemoved(t *testing.T) {
+	changeDir(t, "testdata/g")
+	restoreEnvVars(t)
+
+	withValue := func() *Loader {
+		return New().WithValues(map[string]string{"TEST_VAR3": "values"})
+	}
+
+	diff, err := Compare(New(), withValue())
+	require.NoError(t, err)
+	assert.Equal(t, []KeyDiff{
+		{Key: "TEST_VAR3", Kind: DiffAdded, New: "values"},
+	}, diff.Keys)
+
+	diff, err = Compare(withValue(), New())
+	require.NoError(t, err)
+	assert.Equal(t, []KeyDiff{
+		{Key: "TEST_VAR3", Kind: DiffRemoved, Old: "values"},
+	}, diff.Keys)
+}
+
+func TestCompare_errors(t *testing.T) {
+	changeDir(t, "testdata/g")
+	restoreEnvVars(t)
+
+	failed := New().WithMiddleware(func(next LoadFunc) LoadFunc {
+		return func() error { return errors.New("failed") }
+	})
+
+	_, err := Compare(failed, New())
+	require.ErrorContains(t, err, "resolve first configuration")
+	_, err = Compare(New(), failed)
+	require.ErrorContains(t, err, "resolve second configuration")
+}
+
+func TestDiffKind_String(t *testing.T) {
+	assert.Equal(t, "added", DiffAdded.String())
+	assert.Equal(t, "removed", DiffRemoved.String())
+	assert.Equal(t, "changed", DiffChanged.String())
+	assert.Equal(t, "DiffKind(10)", DiffKind(10).String())
+}
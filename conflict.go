@@ -0,0 +1,60 @@
+package dotenv
+
+import "fmt"
+
+// ConflictResolver returns effective value of env var key, which is defined by
+// two sources: oldSrc with effective value oldVal and newSrc with value newVal.
+// See [Loader.WithConflictResolver].
+type ConflictResolver func(key, oldVal, newVal string, oldSrc, newSrc SourceRef,
+) (string, error)
+
+// WithConflictResolver configures [Loader.Load] to call fn every time an env var
+// is defined by a source, but it's already defined by a source with higher
+// precedence, see [Loader.Precedence], including the process environment.
+// Value returned by fn becomes effective value of the env var and it's applied
+// to the process environment, if it differs from oldVal. So custom policies,
+// like returning an error or preferring longer value, can be implemented on
+// top of built-in first wins order. For instance:
+//
+//	env := dotenv.New().WithConflictResolver(
+//		func(key, oldVal, newVal string, oldSrc, newSrc dotenv.SourceRef,
+//		) (string, error) {
+//			if oldVal != newVal {
+//				return "", fmt.Errorf("%v conflicts with %v", oldSrc, newSrc)
+//			}
+//			return oldVal, nil
+//		})
+//
+// fn is called after all env vars of newSrc are applied, so it can call
+// [Loader.Getenv]. If fn changes effective value, [Loader.Result] reports newSrc
+// as its source. Error of fn fails loading of newSrc.
+func (self *Loader) WithConflictResolver(fn ConflictResolver) *Loader {
+	self.conflictResolver = fn
+	return self
+}
+
+// resolveConflict calls configured conflict resolver for env var key, defined
+// by ref with value, and applies returned value. It must be called without
+// setenvMu held.
+func (self *Loader) resolveConflict(ref SourceRef, key, value string) error {
+	if self.conflictResolver == nil {
+		return nil
+	}
+
+	oldValue, oldRef := self.loaded[key], self.winners[key]
+	v, err := self.conflictResolver(key, oldValue, value, oldRef, ref)
+	if err != nil {
+		return fmt.Errorf("resolve conflict of %v: %w", key, err)
+	} else if v == oldValue {
+		return nil
+	}
+
+	if self.serializeSetenv {
+		setenvMu.Lock()
+		defer setenvMu.Unlock()
+	}
+	self.setenv(ref.String(), key, v)
+	self.loaded[key], self.winners[key] = v, ref
+	self.swapProvenance(ref, key)
+	return nil
+}
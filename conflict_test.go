@@ -0,0 +1,94 @@
+package dotenv
+
+import (
+	"errors"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type conflictCall struct {
+	Key            string
+	OldVal, NewVal string
+	OldSrc, NewSrc SourceRef
+}
+
+func TestLoader_WithConflictResolver(t *testing.T) {
+	changeDir(t, "testdata/g")
+	restoreEnvVars(t)
+	t.Setenv(allEnvVars[1], "env")
+
+	var calls []conflictCall
+	env := New()
+	assert.Same(t, env, env.WithConflictResolver(
+		func(key, oldVal, newVal string, oldSrc, newSrc SourceRef,
+		) (string, error) {
+			calls = append(calls, conflictCall{key, oldVal, newVal, oldSrc, newSrc})
+			if len(newVal) > len(oldVal) {
+				return newVal, nil
+			}
+			return oldVal, nil
+		}))
+	require.NoError(t, env.Load())
+
+	local := SourceRef{Kind: SourceFile, Name: ".env.local"}
+	dotenv := SourceRef{Kind: SourceFile, Name: ".env"}
+	assert.Equal(t, []conflictCall{
+		{allEnvVars[0], "local", "last", local, dotenv},
+		{allEnvVars[1], "env", "second", SourceRef{Kind: SourceEnv}, dotenv},
+	}, calls)
+	assert.Equal(t, "local", os.Getenv(allEnvVars[0]))
+	assert.Equal(t, "second", os.Getenv(allEnvVars[1]))
+
+	res := env.Result()
+	assert.Equal(t, []string{allEnvVars[0], allEnvVars[1]}, res.SetKeys)
+	assert.Equal(t, ".env", res.Source(allEnvVars[1]))
+	assert.Equal(t, []Provenance{
+		{
+			Key:        allEnvVars[0],
+			Source:     local.String(),
+			Overrode:   true,
+			Overridden: []string{dotenv.String()},
+			fname:      local.Name,
+		},
+		{
+			Key:        allEnvVars[1],
+			Source:     dotenv.String(),
+			Overrode:   true,
+			Overridden: []string{SourceRef{Kind: SourceEnv}.String()},
+			fname:      dotenv.Name,
+		},
+	}, res.provenance)
+}
+
+func TestLoader_WithConflictResolver_getenv(t *testing.T) {
+	changeDir(t, "testdata/g")
+	restoreEnvVars(t)
+
+	var got []string
+	env := New().WithSerializedSetenv()
+	env.WithConflictResolver(
+		func(key, oldVal, newVal string, oldSrc, newSrc SourceRef,
+		) (string, error) {
+			got = append(got, env.Getenv(key))
+			return newVal, nil
+		})
+	require.NoError(t, env.Load())
+	assert.Equal(t, []string{"local"}, got)
+	assert.Equal(t, "last", os.Getenv(allEnvVars[0]))
+}
+
+func TestLoader_WithConflictResolver_error(t *testing.T) {
+	changeDir(t, "testdata/g")
+	restoreEnvVars(t)
+
+	errConflict := errors.New("conflict")
+	env := New().WithConflictResolver(
+		func(key, oldVal, newVal string, oldSrc, newSrc SourceRef,
+		) (string, error) {
+			return "", errConflict
+		})
+	require.ErrorIs(t, env.Load(), errConflict)
+}
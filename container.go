@@ -0,0 +1,62 @@
+package dotenv
+
+import (
+	"maps"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// DockerHost is a host name, which containers use to reach services on the
+// host.
+const DockerHost = "host.docker.internal"
+
+// localHostRe matches local host names, which aren't part of other names.
+var localHostRe = regexp.MustCompile(
+	`(^|[^\w.-])(localhost|127\.0\.0\.1|\[::1\])($|[^\w.-])`)
+
+// ContainerEnv returns env vars named keys, suitable for env of a container,
+// like testcontainers.ContainerRequest.Env. Values are from
+// [Loader.LookupEnv] and local host names in them (localhost, 127.0.0.1 and
+// [::1]) are replaced by [DockerHost]. For instance:
+//
+//	DATABASE_URL=postgres://user@localhost:5432/db
+//
+// becomes:
+//
+//	DATABASE_URL=postgres://user@host.docker.internal:5432/db
+//
+// So integration tests can mirror app config into containers. Keys without
+// values are skipped. Without keys it returns all env vars, loaded by last
+// [Loader.Load].
+func (self *Loader) ContainerEnv(keys ...string) map[string]string {
+	if len(keys) == 0 {
+		keys = slices.Collect(maps.Keys(self.loaded))
+	}
+
+	env := make(map[string]string, len(keys))
+	for _, key := range keys {
+		if v, ok := self.LookupEnv(key); ok {
+			env[key] = rewriteLocalHost(v, DockerHost)
+		}
+	}
+	return env
+}
+
+// rewriteLocalHost replaces local host names in s by host.
+func rewriteLocalHost(s, host string) string {
+	var sb strings.Builder
+	for {
+		loc := localHostRe.FindStringSubmatchIndex(s)
+		if loc == nil {
+			break
+		}
+		// loc[4]:loc[5] is a local host name. Its trailing separator can start
+		// next match, so don't consume it.
+		sb.WriteString(s[:loc[4]])
+		sb.WriteString(host)
+		s = s[loc[5]:]
+	}
+	sb.WriteString(s)
+	return sb.String()
+}
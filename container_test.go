@@ -0,0 +1,62 @@
+package dotenv
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoader_ContainerEnv(t *testing.T) {
+	t.Setenv("TEST_DB_URL", "postgres://user@localhost:5432/db")
+	t.Setenv("TEST_REDIS", "127.0.0.1:6379")
+
+	env := New().WithoutSetenv()
+	env.vars = map[string]string{"TEST_NAME": "app"}
+
+	assert.Equal(t, map[string]string{
+		"TEST_DB_URL": "postgres://user@host.docker.internal:5432/db",
+		"TEST_REDIS":  "host.docker.internal:6379",
+		"TEST_NAME":   "app",
+	}, env.ContainerEnv("TEST_DB_URL", "TEST_REDIS", "TEST_NAME",
+		"TEST_NOT_EXISTS"))
+}
+
+func TestLoader_ContainerEnv_loaded(t *testing.T) {
+	changeDir(t, "testdata/g")
+	restoreEnvVars(t)
+	t.Setenv(allEnvVars[1], "http://localhost:8080")
+
+	env := New()
+	assert.Empty(t, env.ContainerEnv())
+	require.NoError(t, env.Load())
+	assert.Equal(t, map[string]string{
+		allEnvVars[0]: "local",
+		allEnvVars[1]: "http://host.docker.internal:8080",
+	}, env.ContainerEnv())
+}
+
+func TestRewriteLocalHost(t *testing.T) {
+	tests := []struct {
+		value  string
+		expect string
+	}{
+		{value: "localhost", expect: DockerHost},
+		{value: "[::1]:8080", expect: DockerHost + ":8080"},
+		{value: "http://localhost/", expect: "http://" + DockerHost + "/"},
+		{
+			value:  "localhost:1,localhost:2",
+			expect: DockerHost + ":1," + DockerHost + ":2",
+		},
+		{value: "localhost.example.com", expect: "localhost.example.com"},
+		{value: "mylocalhost", expect: "mylocalhost"},
+		{value: "127.0.0.10", expect: "127.0.0.10"},
+		{value: "", expect: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			assert.Equal(t, tt.expect, rewriteLocalHost(tt.value, DockerHost))
+		})
+	}
+}
@@ -0,0 +1,111 @@
+package dotenv
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// encPrefix is a prefix of values, encrypted by [EncryptValue].
+const encPrefix = "enc:v1:"
+
+// KeyProvider provides a key for decryption of encrypted values. See
+// [Loader.WithKeyProvider].
+type KeyProvider interface {
+	// Key returns 32 bytes key for AES-256-GCM.
+	Key() ([]byte, error)
+}
+
+// KeyProviderFunc is an adapter to allow the use of ordinary function as
+// [KeyProvider].
+type KeyProviderFunc func() ([]byte, error)
+
+// Key calls fn().
+func (fn KeyProviderFunc) Key() ([]byte, error) { return fn() }
+
+// WithKeyProvider configures [Loader.Load] to decrypt values like:
+//
+//	DB_PASSWORD=enc:v1:...
+//
+// using key from kp. So only specific sensitive values, not whole files, need
+// encryption. Such values can be encrypted by [EncryptValue]. Load asks kp for
+// a key once, when it found first encrypted value. Without kp encrypted values
+// are loaded as is.
+func (self *Loader) WithKeyProvider(kp KeyProvider) *Loader {
+	self.keyProvider = kp
+	return self
+}
+
+// EncryptValue encrypts value of env var named name using AES-256-GCM with 32
+// bytes key and returns it in form, which [Loader.Load] decrypts, if it's
+// configured by [Loader.WithKeyProvider]. Encrypted value is bound to name
+// and can't be moved to another env var.
+func EncryptValue(key []byte, name, value string) (string, error) {
+	aead, err := newAEAD(key)
+	if err != nil {
+		return "", err
+	}
+
+	nonce := make([]byte, aead.NonceSize(), aead.NonceSize()+len(value)+
+		aead.Overhead())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", fmt.Errorf("generate nonce: %w", err)
+	}
+
+	b := aead.Seal(nonce, nonce, []byte(value), []byte(name))
+	return encPrefix + base64.StdEncoding.EncodeToString(b), nil
+}
+
+// decryptValue decrypts value of env var named name, if it was encrypted by
+// [EncryptValue] and [KeyProvider] is configured, and returns value as is
+// otherwise.
+func (self *Loader) decryptValue(name, value string) (string, error) {
+	b64, ok := strings.CutPrefix(value, encPrefix)
+	if !ok || self.keyProvider == nil {
+		return value, nil
+	}
+
+	if self.aead == nil {
+		key, err := self.keyProvider.Key()
+		if err != nil {
+			return "", fmt.Errorf("get key: %w", err)
+		} else if self.aead, err = newAEAD(key); err != nil {
+			return "", err
+		}
+	}
+
+	b, err := base64.StdEncoding.DecodeString(b64)
+	if err != nil {
+		return "", fmt.Errorf("decode encrypted value: %w", err)
+	} else if len(b) < self.aead.NonceSize() {
+		return "", errors.New("encrypted value is too short")
+	}
+
+	nonce, ciphertext := b[:self.aead.NonceSize()], b[self.aead.NonceSize():]
+	plaintext, err := self.aead.Open(nil, nonce, ciphertext, []byte(name))
+	if err != nil {
+		return "", fmt.Errorf("decrypt value: %w", err)
+	}
+	return string(plaintext), nil
+}
+
+func newAEAD(key []byte) (cipher.AEAD, error) {
+	if len(key) != 32 {
+		return nil, fmt.Errorf("expected 32 bytes key, got %v", len(key))
+	}
+
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("create AES cipher: %w", err)
+	}
+
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, fmt.Errorf("create GCM: %w", err)
+	}
+	return aead, nil
+}
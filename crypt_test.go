@@ -0,0 +1,73 @@
+package dotenv
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEncryptValue(t *testing.T) {
+	key := bytes.Repeat([]byte{1}, 32)
+	encrypted, err := EncryptValue(key, "NAME", "secret")
+	require.NoError(t, err)
+	assert.Contains(t, encrypted, encPrefix)
+
+	env := New().WithKeyProvider(KeyProviderFunc(func() ([]byte, error) {
+		return key, nil
+	}))
+	assert.Equal(t, "secret", valueNoError[string](t)(
+		env.decryptValue("NAME", encrypted)))
+	assert.Equal(t, "plain", valueNoError[string](t)(
+		env.decryptValue("NAME", "plain")))
+
+	_, err = env.decryptValue("OTHER_NAME", encrypted)
+	require.Error(t, err, "bound to name")
+
+	_, err = env.decryptValue("NAME", encPrefix+"!")
+	require.Error(t, err, "invalid base64")
+
+	_, err = env.decryptValue("NAME", encPrefix+"AAAA")
+	require.Error(t, err, "too short")
+
+	_, err = EncryptValue([]byte("short"), "NAME", "secret")
+	require.Error(t, err)
+}
+
+func TestLoader_WithKeyProvider(t *testing.T) {
+	key := bytes.Repeat([]byte{1}, 32)
+	encrypted := valueNoError[string](t)(EncryptValue(key, allEnvVars[0], "secret"))
+
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, ".env"),
+		[]byte(allEnvVars[0]+"="+encrypted+"\n"), 0o600))
+	changeDir(t, dir)
+	restoreEnvVars(t)
+
+	var keyCalls int
+	env := New().WithRootDir(dir).WithKeyProvider(KeyProviderFunc(
+		func() ([]byte, error) {
+			keyCalls++
+			return key, nil
+		}))
+	require.NoError(t, env.Load())
+	assert.Equal(t, "secret", os.Getenv(allEnvVars[0]))
+	assert.Equal(t, 1, keyCalls)
+
+	restoreEnvVars(t)
+	require.NoError(t, New().WithRootDir(dir).Load())
+	assert.Equal(t, encrypted, os.Getenv(allEnvVars[0]), "without key provider")
+
+	errKey := errors.New("key error")
+	env = New().WithRootDir(dir).WithKeyProvider(KeyProviderFunc(
+		func() ([]byte, error) { return nil, errKey }))
+	require.ErrorIs(t, env.Load(), errKey)
+
+	env = New().WithRootDir(dir).WithKeyProvider(KeyProviderFunc(
+		func() ([]byte, error) { return []byte("short"), nil }))
+	require.Error(t, env.Load())
+}
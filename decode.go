@@ -0,0 +1,414 @@
+package dotenv
+
+import (
+	"encoding"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DefaultSeparator is a separator of nested keys, used by [Unflatten] and
+// [Decode] by default. With it "FOO__BAR__BAZ" means key "BAZ" inside of "BAR"
+// inside of "FOO".
+const DefaultSeparator = "__"
+
+// ErrNotStructPtr returned by [Decode] if it got something else as a target.
+var ErrNotStructPtr = errors.New("expected non-nil pointer to a struct")
+
+// Environ returns current environment variables as a map.
+func Environ() map[string]string { return environMap(os.Environ()) }
+
+// environMap converts environ in the form "key=value" into a map.
+func environMap(environ []string) map[string]string {
+	vars := make(map[string]string, len(environ))
+	for _, s := range environ {
+		if k, v, ok := strings.Cut(s, "="); ok {
+			vars[k] = v
+		}
+	}
+	return vars
+}
+
+// Unflatten interprets keys of vars as paths, separated by sep, and returns
+// nested structure. For instance with sep == "__" this vars:
+//
+//	FOO__BAR__BAZ=1
+//	FOO__QUX=2
+//
+// becomes:
+//
+//	map[string]any{
+//		"FOO": map[string]any{
+//			"BAR": map[string]any{"BAZ": "1"},
+//			"QUX": "2",
+//		},
+//	}
+//
+// It returns an error if the same key is a value and a nested structure at the
+// same time, like "FOO=1" and "FOO__BAR=2".
+func Unflatten(vars map[string]string, sep string) (map[string]any, error) {
+	return unflatten(vars, sep, func(key, value string) any { return value })
+}
+
+// unflatten works like [Unflatten], but sets every value using leaf, which
+// gets original key and value.
+func unflatten(vars map[string]string, sep string,
+	leaf func(key, value string) any,
+) (map[string]any, error) {
+	if sep == "" {
+		sep = DefaultSeparator
+	}
+
+	root := make(map[string]any)
+	for key, value := range vars {
+		path := strings.Split(key, sep)
+		node := root
+		for i, name := range path[:len(path)-1] {
+			switch child := node[name].(type) {
+			case nil:
+				m := make(map[string]any)
+				node[name], node = m, m
+			case map[string]any:
+				node = child
+			default:
+				return nil, fmt.Errorf("key %q conflicts with value of %q", key,
+					strings.Join(path[:i+1], sep))
+			}
+		}
+
+		name := path[len(path)-1]
+		if _, ok := node[name].(map[string]any); ok {
+			return nil, fmt.Errorf("value of %q conflicts with nested keys", key)
+		}
+		node[name] = leaf(key, value)
+	}
+
+	return root, nil
+}
+
+// DecodeOption configures [Decode] somehow.
+type DecodeOption func(d *decoder)
+
+// WithSeparator configures [Decode] to use sep as separator of nested keys
+// instead of [DefaultSeparator].
+func WithSeparator(sep string) DecodeOption {
+	return func(d *decoder) { d.sep = sep }
+}
+
+// WithJSONValues configures [Decode] to parse values of slice and map fields as
+// JSON, if they start with "[" or "{". For instance:
+//
+//	HOSTS=["a.example.com", "b.example.com"]
+//	LIMITS={"cpu": 2, "mem": 512}
+//
+// decodes into fields of type []string and map[string]int. Maps still can be
+// decoded from nested keys and slices from comma separated values, if they
+// have no JSON value.
+func WithJSONValues() DecodeOption {
+	return func(d *decoder) { d.json = true }
+}
+
+// Decode decodes vars into a struct, pointed by v. Every exported field is
+// decoded from a key, defined by its "env" tag or from upper cased name of the
+// field. Fields with "env" tag "-" are skipped.
+//
+// Nested structs and maps are decoded from keys with name of the field as
+// prefix, followed by a separator, see [WithSeparator]. For instance:
+//
+//	cfg := struct {
+//		Database struct {
+//			Host string
+//			Port int
+//		} `env:"DB"`
+//		Labels map[string]string
+//	}{}
+//
+//	err := dotenv.Decode(dotenv.Environ(), &cfg)
+//
+// decodes "DB__HOST", "DB__PORT", "LABELS__A", "LABELS__B" and so on.
+//
+// Values are parsed according to type of field. Besides of strings, bools and
+// numbers, it supports [time.Duration], [ByteSize], [url.URL], any type
+// implementing [encoding.TextUnmarshaler], like [net/netip.Addr] and
+// [net/netip.AddrPort], and slices of them as comma separated values. Fields of
+// type any get values as strings or as [Secret], see [WithSecretKeys].
+//
+// Fields without any value in vars keep their current values.
+func Decode(vars map[string]string, v any, opts ...DecodeOption) error {
+	d := decoder{vars: vars, sep: DefaultSeparator}
+	for _, opt := range opts {
+		opt(&d)
+	}
+
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Pointer || rv.IsNil() ||
+		rv.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("decode %T: %w", v, ErrNotStructPtr)
+	}
+	return d.decodeStruct(rv.Elem(), "")
+}
+
+type decoder struct {
+	vars    map[string]string
+	sep     string
+	json    bool
+	secrets []string
+}
+
+func (self *decoder) decodeStruct(rv reflect.Value, prefix string) error {
+	rt := rv.Type()
+	for i := range rt.NumField() {
+		name, ok := fieldKey(rt.Field(i))
+		if !ok {
+			continue
+		} else if err := self.decodeValue(rv.Field(i), prefix+name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// fieldKey returns name of key for field and true, or false if field must be
+// skipped.
+func fieldKey(field reflect.StructField) (string, bool) {
+	if !field.IsExported() {
+		return "", false
+	}
+
+	name := strings.ToUpper(field.Name)
+	if tag, ok := field.Tag.Lookup("env"); ok {
+		if tag == "-" {
+			return "", false
+		} else if tag != "" {
+			name = tag
+		}
+	}
+	return name, true
+}
+
+func (self *decoder) decodeValue(rv reflect.Value, key string) error {
+	switch rv.Kind() { //nolint:exhaustive // everything else is a scalar
+	case reflect.Struct:
+		if isTextValue(rv.Type()) {
+			break
+		}
+		return self.decodeStruct(rv, key+self.sep)
+	case reflect.Map:
+		if ok, err := self.decodeJSON(rv, key); err != nil || ok {
+			return err
+		}
+		return self.decodeMap(rv, key+self.sep)
+	case reflect.Slice:
+		if ok, err := self.decodeJSON(rv, key); err != nil || ok {
+			return err
+		}
+	case reflect.Pointer:
+		if !self.hasPrefix(key) {
+			return nil
+		} else if rv.IsNil() {
+			rv.Set(reflect.New(rv.Type().Elem()))
+		}
+		return self.decodeValue(rv.Elem(), key)
+	}
+
+	s, ok := self.vars[key]
+	if !ok {
+		return nil
+	} else if rv.Kind() == reflect.Interface && rv.NumMethod() == 0 {
+		rv.Set(reflect.ValueOf(self.anyValue(key, s)))
+		return nil
+	} else if err := setValue(rv, s); err != nil {
+		return fmt.Errorf("decode %v: %w", key, err)
+	}
+	return nil
+}
+
+// decodeJSON decodes value of key into rv as JSON, if it's configured by
+// [WithJSONValues] and the value looks like JSON array or object. It returns
+// true, if rv was decoded.
+func (self *decoder) decodeJSON(rv reflect.Value, key string) (bool, error) {
+	if !self.json {
+		return false, nil
+	}
+
+	s, ok := self.vars[key]
+	if !ok {
+		return false, nil
+	}
+
+	s = strings.TrimSpace(s)
+	if !strings.HasPrefix(s, "[") && !strings.HasPrefix(s, "{") {
+		return false, nil
+	} else if err := json.Unmarshal([]byte(s), rv.Addr().Interface()); err != nil {
+		return false, fmt.Errorf("decode %v as JSON: %w", key, err)
+	}
+	return true, nil
+}
+
+// hasPrefix returns true if vars contains key itself or any key nested into
+// it.
+func (self *decoder) hasPrefix(key string) bool {
+	if _, ok := self.vars[key]; ok {
+		return true
+	}
+	for k := range self.vars {
+		if strings.HasPrefix(k, key+self.sep) {
+			return true
+		}
+	}
+	return false
+}
+
+func (self *decoder) decodeMap(rv reflect.Value, prefix string) error {
+	rt := rv.Type()
+	if rt.Key().Kind() != reflect.String {
+		return fmt.Errorf("decode %v: unsupported map key type %v",
+			strings.TrimSuffix(prefix, self.sep), rt.Key())
+	}
+
+	sub := make(map[string]string)
+	for k, v := range self.vars {
+		if name, ok := strings.CutPrefix(k, prefix); ok && name != "" {
+			sub[name] = v
+		}
+	}
+	if len(sub) == 0 {
+		return nil
+	} else if rv.IsNil() {
+		rv.Set(reflect.MakeMapWithSize(rt, len(sub)))
+	}
+
+	if rt.Elem().Kind() == reflect.Interface && rt.Elem().NumMethod() == 0 {
+		nested, err := unflatten(sub, self.sep, func(key, value string) any {
+			return self.anyValue(prefix+key, value)
+		})
+		if err != nil {
+			return fmt.Errorf("decode %v: %w",
+				strings.TrimSuffix(prefix, self.sep), err)
+		}
+		for k, v := range nested {
+			rv.SetMapIndex(reflect.ValueOf(k).Convert(rt.Key()), reflect.ValueOf(v))
+		}
+		return nil
+	}
+
+	for name, s := range sub {
+		elem := reflect.New(rt.Elem()).Elem()
+		if err := setValue(elem, s); err != nil {
+			return fmt.Errorf("decode %v: %w", prefix+name, err)
+		}
+		rv.SetMapIndex(reflect.ValueOf(name).Convert(rt.Key()), elem)
+	}
+	return nil
+}
+
+var (
+	durationType = reflect.TypeFor[time.Duration]()
+	urlType      = reflect.TypeFor[url.URL]()
+	textType     = reflect.TypeFor[encoding.TextUnmarshaler]()
+)
+
+// isTextValue returns true if value of type rt is parsed from a string as a
+// whole, even if it's a struct.
+func isTextValue(rt reflect.Type) bool {
+	return rt == durationType || rt == urlType ||
+		reflect.PointerTo(rt).Implements(textType)
+}
+
+// setValue parses s according to type of rv and sets rv to parsed value. It
+// supports:
+//
+//   - string, bool, ints, uints and floats.
+//   - [time.Duration], like "1m30s".
+//   - [url.URL].
+//   - Any type, which implements [encoding.TextUnmarshaler], like
+//     [net/netip.Addr], [net/netip.AddrPort] and [ByteSize].
+//   - Slices of all above as comma separated values.
+func setValue(rv reflect.Value, s string) error {
+	switch rt := rv.Type(); {
+	case rt == durationType:
+		d, err := time.ParseDuration(s)
+		if err != nil {
+			return fmt.Errorf("parse duration: %w", err)
+		}
+		rv.SetInt(int64(d))
+		return nil
+	case rt == urlType:
+		u, err := url.Parse(s)
+		if err != nil {
+			return fmt.Errorf("parse url: %w", err)
+		}
+		rv.Set(reflect.ValueOf(*u))
+		return nil
+	case reflect.PointerTo(rt).Implements(textType):
+		err := rv.Addr().Interface().(encoding.TextUnmarshaler).UnmarshalText(
+			[]byte(s))
+		if err != nil {
+			return fmt.Errorf("parse %v: %w", rt, err)
+		}
+		return nil
+	case rt.Kind() == reflect.Slice:
+		return setSlice(rv, s)
+	}
+	return setScalar(rv, s)
+}
+
+// setSlice parses s as comma separated values and sets rv to a slice of them.
+// Empty s means empty slice.
+func setSlice(rv reflect.Value, s string) error {
+	var items []string
+	if s = strings.TrimSpace(s); s != "" {
+		items = strings.Split(s, ",")
+	}
+
+	slice := reflect.MakeSlice(rv.Type(), len(items), len(items))
+	for i, item := range items {
+		if err := setValue(slice.Index(i), strings.TrimSpace(item)); err != nil {
+			return fmt.Errorf("item #%v: %w", i, err)
+		}
+	}
+	rv.Set(slice)
+	return nil
+}
+
+// setScalar parses s according to kind of rv and sets rv to parsed value.
+func setScalar(rv reflect.Value, s string) error {
+	switch rv.Kind() { //nolint:exhaustive // unsupported kinds handled below
+	case reflect.String:
+		rv.SetString(s)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(s)
+		if err != nil {
+			return fmt.Errorf("parse bool: %w", err)
+		}
+		rv.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(s, 10, rv.Type().Bits())
+		if err != nil {
+			return fmt.Errorf("parse int: %w", err)
+		}
+		rv.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32,
+		reflect.Uint64:
+		n, err := strconv.ParseUint(s, 10, rv.Type().Bits())
+		if err != nil {
+			return fmt.Errorf("parse uint: %w", err)
+		}
+		rv.SetUint(n)
+	case reflect.Float32, reflect.Float64:
+		f, err := strconv.ParseFloat(s, rv.Type().Bits())
+		if err != nil {
+			return fmt.Errorf("parse float: %w", err)
+		}
+		rv.SetFloat(f)
+	default:
+		return fmt.Errorf("unsupported type %v", rv.Type())
+	}
+	return nil
+}
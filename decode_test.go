@@ -0,0 +1,413 @@
+package dotenv
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/netip"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEnviron(t *testing.T) {
+	t.Setenv("TEST_VAR1", "a=b")
+	vars := Environ()
+	assert.Equal(t, "a=b", vars["TEST_VAR1"])
+}
+
+func TestUnflatten(t *testing.T) {
+	tests := []struct {
+		name    string
+		vars    map[string]string
+		sep     string
+		expect  map[string]any
+		wantErr bool
+	}{
+		{
+			name:   "empty",
+			vars:   map[string]string{},
+			expect: map[string]any{},
+		},
+		{
+			name: "nested with default separator",
+			vars: map[string]string{
+				"FOO__BAR__BAZ": "1",
+				"FOO__QUX":      "2",
+				"TOP":           "3",
+			},
+			expect: map[string]any{
+				"FOO": map[string]any{
+					"BAR": map[string]any{"BAZ": "1"},
+					"QUX": "2",
+				},
+				"TOP": "3",
+			},
+		},
+		{
+			name: "with custom separator",
+			vars: map[string]string{"FOO.BAR": "1", "FOO__BAR": "2"},
+			sep:  ".",
+			expect: map[string]any{
+				"FOO":      map[string]any{"BAR": "1"},
+				"FOO__BAR": "2",
+			},
+		},
+		{
+			name:    "value conflicts with nested keys",
+			vars:    map[string]string{"FOO": "1", "FOO__BAR": "2"},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Unflatten(tt.vars, tt.sep)
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expect, got)
+		})
+	}
+}
+
+func TestDecode(t *testing.T) {
+	type dbConfig struct {
+		Host string
+		Port int
+	}
+
+	type config struct {
+		Name     string `env:"APP_NAME"`
+		Debug    bool
+		Ratio    float64
+		Count    uint8
+		Skipped  string   `env:"-"`
+		Database dbConfig `env:"DB"`
+		Replica  *dbConfig
+		Labels   map[string]string
+		Extra    map[string]any
+		Default  string
+		private  string
+	}
+
+	vars := map[string]string{
+		"APP_NAME":           "test",
+		"DEBUG":              "true",
+		"RATIO":              "0.5",
+		"COUNT":              "12",
+		"SKIPPED":            "skipped",
+		"DB__HOST":           "localhost",
+		"DB__PORT":           "5432",
+		"LABELS__A":          "1",
+		"LABELS__B":          "2",
+		"EXTRA__FOO__BAR":    "1",
+		"EXTRA__BAZ":         "2",
+		"PRIVATE":            "private",
+		"UNKNOWN__FOO":       "unknown",
+		"REPLICATED__HOST__": "not a replica",
+	}
+
+	cfg := config{Default: "default"}
+	require.NoError(t, Decode(vars, &cfg))
+	assert.Equal(t, config{
+		Name:     "test",
+		Debug:    true,
+		Ratio:    0.5,
+		Count:    12,
+		Database: dbConfig{Host: "localhost", Port: 5432},
+		Labels:   map[string]string{"A": "1", "B": "2"},
+		Extra: map[string]any{
+			"FOO": map[string]any{"BAR": "1"},
+			"BAZ": "2",
+		},
+		Default: "default",
+	}, cfg)
+
+	vars["REPLICA.HOST"] = "replica"
+	require.NoError(t, Decode(vars, &cfg, WithSeparator(".")))
+	require.NotNil(t, cfg.Replica)
+	assert.Equal(t, "replica", cfg.Replica.Host)
+}
+
+func TestDecode_errors(t *testing.T) {
+	tests := []struct {
+		name   string
+		vars   map[string]string
+		target any
+	}{
+		{
+			name:   "not a pointer",
+			target: struct{}{},
+		},
+		{
+			name:   "nil pointer",
+			target: (*struct{})(nil),
+		},
+		{
+			name:   "pointer to not a struct",
+			target: new(string),
+		},
+		{
+			name:   "invalid int",
+			vars:   map[string]string{"PORT": "abc"},
+			target: &struct{ Port int }{},
+		},
+		{
+			name:   "invalid bool",
+			vars:   map[string]string{"DEBUG": "abc"},
+			target: &struct{ Debug bool }{},
+		},
+		{
+			name:   "invalid uint",
+			vars:   map[string]string{"COUNT": "-1"},
+			target: &struct{ Count uint }{},
+		},
+		{
+			name:   "invalid float",
+			vars:   map[string]string{"RATIO": "abc"},
+			target: &struct{ Ratio float32 }{},
+		},
+		{
+			name:   "invalid map value",
+			vars:   map[string]string{"PORTS__A": "abc"},
+			target: &struct{ Ports map[string]int }{},
+		},
+		{
+			name:   "unsupported map key",
+			vars:   map[string]string{"PORTS__1": "1"},
+			target: &struct{ Ports map[int]string }{},
+		},
+		{
+			name:   "unsupported type",
+			vars:   map[string]string{"FN": "1"},
+			target: &struct{ Fn func() }{},
+		},
+		{
+			name:   "conflicting nested keys",
+			vars:   map[string]string{"EXTRA__A": "1", "EXTRA__A__B": "2"},
+			target: &struct{ Extra map[string]any }{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Error(t, Decode(tt.vars, tt.target))
+		})
+	}
+}
+
+func TestDecode_withJSONValues(t *testing.T) {
+	type config struct {
+		Hosts  []string
+		Limits map[string]int
+		Labels map[string]string
+	}
+
+	vars := map[string]string{
+		"HOSTS":     ` ["a.example.com", "b.example.com"]`,
+		"LIMITS":    `{"cpu": 2, "mem": 512}`,
+		"LABELS__A": "1",
+	}
+
+	var cfg config
+	require.NoError(t, Decode(vars, &cfg), "comma separated without JSON")
+	assert.Equal(t, []string{`["a.example.com"`, `"b.example.com"]`}, cfg.Hosts)
+
+	cfg = config{}
+	require.NoError(t, Decode(vars, &cfg, WithJSONValues()))
+	assert.Equal(t, config{
+		Hosts:  []string{"a.example.com", "b.example.com"},
+		Limits: map[string]int{"cpu": 2, "mem": 512},
+		Labels: map[string]string{"A": "1"},
+	}, cfg)
+
+	vars["HOSTS"] = "a.example.com"
+	require.NoError(t, Decode(vars, &cfg, WithJSONValues()), "not a JSON")
+	assert.Equal(t, []string{"a.example.com"}, cfg.Hosts)
+
+	vars["HOSTS"] = `["a.example.com"`
+	require.Error(t, Decode(vars, &cfg, WithJSONValues()), "invalid JSON")
+}
+
+func TestDecode_types(t *testing.T) {
+	type config struct {
+		Timeout  time.Duration
+		MaxBody  ByteSize
+		Endpoint *url.URL
+		Base     url.URL
+		Addr     netip.Addr
+		Listen   netip.AddrPort
+		Hosts    []string
+		Ports    []int
+		Delays   []time.Duration
+		Empty    []string
+	}
+
+	vars := map[string]string{
+		"TIMEOUT":  "1m30s",
+		"MAXBODY":  "512MiB",
+		"ENDPOINT": "https://example.com/api",
+		"BASE":     "http://localhost",
+		"ADDR":     "10.0.0.1",
+		"LISTEN":   "[::1]:8080",
+		"HOSTS":    "a.example.com, b.example.com",
+		"PORTS":    "80,443",
+		"DELAYS":   "1s,2s",
+		"EMPTY":    "",
+	}
+
+	var cfg config
+	require.NoError(t, Decode(vars, &cfg))
+	assert.Equal(t, 90*time.Second, cfg.Timeout)
+	assert.Equal(t, ByteSize(512<<20), cfg.MaxBody)
+	require.NotNil(t, cfg.Endpoint)
+	assert.Equal(t, "https://example.com/api", cfg.Endpoint.String())
+	assert.Equal(t, "localhost", cfg.Base.Host)
+	assert.Equal(t, netip.MustParseAddr("10.0.0.1"), cfg.Addr)
+	assert.Equal(t, netip.MustParseAddrPort("[::1]:8080"), cfg.Listen)
+	assert.Equal(t, []string{"a.example.com", "b.example.com"}, cfg.Hosts)
+	assert.Equal(t, []int{80, 443}, cfg.Ports)
+	assert.Equal(t, []time.Duration{time.Second, 2 * time.Second}, cfg.Delays)
+	assert.Empty(t, cfg.Empty)
+
+	var padded struct {
+		Mode  int
+		Day   uint8
+		Ports []int
+	}
+	require.NoError(t, Decode(map[string]string{
+		"MODE": "010", "DAY": "08", "PORTS": "080,0443",
+	}, &padded))
+	assert.Equal(t, 10, padded.Mode)
+	assert.Equal(t, uint8(8), padded.Day)
+	assert.Equal(t, []int{80, 443}, padded.Ports)
+
+	tests := []struct {
+		name   string
+		vars   map[string]string
+		target any
+	}{
+		{
+			name:   "invalid duration",
+			vars:   map[string]string{"TIMEOUT": "1x"},
+			target: &struct{ Timeout time.Duration }{},
+		},
+		{
+			name:   "invalid url",
+			vars:   map[string]string{"URL": "://"},
+			target: &struct{ URL url.URL }{},
+		},
+		{
+			name:   "invalid addr",
+			vars:   map[string]string{"ADDR": "1.2.3"},
+			target: &struct{ Addr netip.Addr }{},
+		},
+		{
+			name:   "hex int",
+			vars:   map[string]string{"PORT": "0x50"},
+			target: &struct{ Port int }{},
+		},
+		{
+			name:   "invalid slice item",
+			vars:   map[string]string{"PORTS": "80,abc"},
+			target: &struct{ Ports []int }{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Error(t, Decode(tt.vars, tt.target))
+		})
+	}
+}
+
+func TestByteSize_UnmarshalText(t *testing.T) {
+	tests := []struct {
+		text    string
+		expect  ByteSize
+		wantErr bool
+	}{
+		{text: "1024", expect: 1024},
+		{text: "10B", expect: 10},
+		{text: "1kb", expect: 1000},
+		{text: "1.5 GB", expect: 1_500_000_000},
+		{text: "512MiB", expect: 512 << 20},
+		{text: "2TiB", expect: 2 << 40},
+		{text: "1PiB", expect: 1 << 50},
+		{text: "1XB", wantErr: true},
+		{text: "MiB", wantErr: true},
+		{text: "1.2.3MB", wantErr: true},
+		{text: "100000000PB", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.text, func(t *testing.T) {
+			var size ByteSize
+			err := size.UnmarshalText([]byte(tt.text))
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expect, size)
+		})
+	}
+}
+
+func TestSecret(t *testing.T) {
+	s := Secret("password")
+	assert.Equal(t, "password", s.Value())
+	assert.Equal(t, "***", s.String())
+	assert.Equal(t, "***", fmt.Sprint(s))
+	assert.Equal(t, "***", fmt.Sprintf("%#v", s))
+	assert.Equal(t, "***", s.LogValue().String())
+
+	b, err := json.Marshal(struct{ S Secret }{s})
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"S": "***"}`, string(b))
+}
+
+func TestDecode_withSecretKeys(t *testing.T) {
+	type config struct {
+		Password Secret
+		Token    any
+		User     any
+		Extra    map[string]any
+	}
+
+	vars := map[string]string{
+		"PASSWORD":           "secret1",
+		"TOKEN":              "secret2",
+		"USER":               "user",
+		"EXTRA__DB__PASS":    "secret3",
+		"EXTRA__DB__HOST":    "localhost",
+		"EXTRA__API_TOKEN_X": "secret4",
+	}
+
+	var cfg config
+	require.NoError(t, Decode(vars, &cfg,
+		WithSecretKeys("*PASS*", "*TOKEN*")))
+	assert.Equal(t, config{
+		Password: Secret("secret1"),
+		Token:    Secret("secret2"),
+		User:     "user",
+		Extra: map[string]any{
+			"DB": map[string]any{
+				"PASS": Secret("secret3"),
+				"HOST": "localhost",
+			},
+			"API_TOKEN_X": Secret("secret4"),
+		},
+	}, cfg)
+
+	cfg = config{}
+	require.NoError(t, Decode(vars, &cfg))
+	assert.Equal(t, "secret2", cfg.Token)
+	assert.Equal(t, "secret3",
+		cfg.Extra["DB"].(map[string]any)["PASS"]) //nolint:forcetypeassert // test
+}
@@ -0,0 +1,79 @@
+package dotenv
+
+import (
+	"errors"
+	"maps"
+	"slices"
+)
+
+// ErrDeprecatedKey is wrapped by every [DeprecatedKeyError].
+var ErrDeprecatedKey = errors.New("deprecated key")
+
+// DeprecatedKeyError is a warning of [Loader.Load], if any source defines a key,
+// configured by [Loader.WithDeprecatedKeys]. Use [errors.As] to get it from
+// [Result.Warnings].
+type DeprecatedKeyError struct {
+	// Key is a name of deprecated key.
+	Key string
+
+	// Source is a source, which defines Key, like ".env" or "env" for the
+	// process environment.
+	Source string
+
+	// Message is a message about Key, like its replacement.
+	Message string
+}
+
+func (self *DeprecatedKeyError) Error() string {
+	s := "deprecated key " + self.Key + " from " + self.Source
+	if self.Message != "" {
+		s += ": " + self.Message
+	}
+	return s
+}
+
+// Unwrap returns [ErrDeprecatedKey].
+func (self *DeprecatedKeyError) Unwrap() error { return ErrDeprecatedKey }
+
+// WithDeprecatedKeys configures [Loader.Load] to add a warning
+// [DeprecatedKeyError] for every key of deprecated, defined by any source,
+// including the process environment. deprecated maps keys to messages, like
+// their replacements:
+//
+//	env := dotenv.New().WithDeprecatedKeys(map[string]string{
+//		"REDIS_HOST": "use REDIS_URL",
+//	})
+//
+// Warnings are structured, so platform teams can drive migrations of config
+// through telemetry, see [Loader.WithWarningHook].
+func (self *Loader) WithDeprecatedKeys(deprecated map[string]string) *Loader {
+	self.deprecated = deprecated
+	return self
+}
+
+// checkDeprecated adds warnings about deprecated keys of vars, loaded from
+// source.
+func (self *Loader) checkDeprecated(source string, vars map[string]string) {
+	for _, key := range slices.Sorted(maps.Keys(vars)) {
+		if msg, ok := self.deprecated[key]; ok {
+			self.warnings = append(self.warnings,
+				&DeprecatedKeyError{Key: key, Source: source, Message: msg})
+		}
+	}
+}
+
+// checkDeprecatedEnv adds warnings about deprecated keys, defined by the
+// process environment only.
+func (self *Loader) checkDeprecatedEnv() {
+	for _, key := range slices.Sorted(maps.Keys(self.deprecated)) {
+		if _, ok := self.sources[key]; ok {
+			continue
+		} else if _, ok := self.LookupEnv(key); ok {
+			self.warnings = append(self.warnings, &DeprecatedKeyError{
+				Key:     key,
+				Source:  SourceEnv.String(),
+				Message: self.deprecated[key],
+			})
+		}
+	}
+}
@@ -0,0 +1,41 @@
+package dotenv
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoader_WithDeprecatedKeys(t *testing.T) {
+	changeDir(t, "testdata/g")
+	restoreEnvVars(t)
+	t.Setenv("TEST_DEPRECATED", "1")
+
+	env := New()
+	assert.Same(t, env, env.WithDeprecatedKeys(map[string]string{
+		allEnvVars[0]:     "use TEST_VAR3",
+		"TEST_DEPRECATED": "",
+		"NOT_EXISTS":      "",
+	}))
+	require.NoError(t, env.Load())
+
+	var got []DeprecatedKeyError
+	for _, warn := range env.Result().Warnings {
+		require.ErrorIs(t, warn, ErrDeprecatedKey)
+		var depErr *DeprecatedKeyError
+		require.True(t, errors.As(warn, &depErr))
+		got = append(got, *depErr)
+	}
+	assert.Equal(t, []DeprecatedKeyError{
+		{Key: allEnvVars[0], Source: ".env.local", Message: "use TEST_VAR3"},
+		{Key: allEnvVars[0], Source: ".env", Message: "use TEST_VAR3"},
+		{Key: "TEST_DEPRECATED", Source: "env"},
+	}, got)
+
+	assert.Equal(t, "deprecated key TEST_VAR1 from .env: use TEST_VAR3",
+		env.Result().Warnings[1].Error())
+	assert.Equal(t, "deprecated key TEST_DEPRECATED from env",
+		env.Result().Warnings[2].Error())
+}
@@ -0,0 +1,119 @@
+package dotenv
+
+import (
+	"cmp"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// Entries returns entries of current dir, sorted by name, like [os.ReadDir]
+// does. They are read once per dir and shared with checks of existence of .env
+// files and with matching of glob patterns of [Loader.WithRootFiles], so
+// callbacks, which examine contents of dir, don't double syscall cost of
+// lookup:
+//
+//	env := dotenv.New().WithWalkCallback(func(info dotenv.WalkInfo) (bool, error) {
+//		entries, err := info.Entries()
+//		if err != nil {
+//			return false, err
+//		}
+//		return slices.ContainsFunc(entries, func(e fs.DirEntry) bool {
+//			return e.IsDir() && e.Name() == ".git"
+//		}), nil
+//	})
+//
+// Entries are read from [Loader.WithFS] or from the OS filesystem. It must be
+// called by the callback only.
+func (self WalkInfo) Entries() ([]fs.DirEntry, error) {
+	if self.loader == nil {
+		return nil, nil
+	}
+	return self.loader.readDir(self.Path)
+}
+
+// dirEntries is a cached result of reading a dir.
+type dirEntries struct {
+	dir     string
+	entries []fs.DirEntry
+	err     error
+}
+
+// dirReader is implemented by [Filer], which reads dirs too, like replay of a
+// [Recording] does.
+type dirReader interface {
+	ReadDir(name string) ([]fs.DirEntry, error)
+}
+
+// readDir returns entries of dir from configured [Filer], if it implements
+// dirReader, [fs.FS] or from the OS filesystem. Lookup visits every dir once,
+// so it caches entries of the last read dir only.
+func (self *Loader) readDir(dir string) ([]fs.DirEntry, error) {
+	if err := self.checkContext(); err != nil {
+		return nil, err
+	} else if c := self.dirCache; c != nil && c.dir == dir {
+		return c.entries, c.err
+	}
+	self.touchPath(dir)
+
+	var entries []fs.DirEntry
+	var err error
+	if r, ok := self.filer.(dirReader); ok {
+		entries, err = r.ReadDir(dir)
+	} else if self.fsys != nil {
+		entries, err = fs.ReadDir(self.fsys, fsName(dir))
+	} else {
+		entries, err = os.ReadDir(dir)
+	}
+	self.stats.ReadDirCalls++
+	self.recordDir(dir, entries, err)
+	self.dirCache = &dirEntries{dir: dir, entries: entries, err: err}
+	return entries, err //nolint:wrapcheck // caller wraps it
+}
+
+// renameDirCache makes cached entries of dir known as entries of newDir, like
+// entries of current dir, which lookup reads as "." first and then checks for
+// root files by its absolute path.
+func (self *Loader) renameDirCache(dir, newDir string) {
+	if c := self.dirCache; c != nil && c.dir == dir {
+		c.dir = newDir
+	}
+}
+
+// cachedExists checks if file fname exists in dir using entries of dir, see
+// readDir, so lookup reads every dir once, instead of calling Stat for every
+// candidate file. ok is false, if entries can't answer and caller must call
+// Stat: custom [Filer] is configured, fname isn't a plain name, dir can't be
+// read, fname is a symlink, which must be followed, or fname isn't found, but
+// dir has an entry, which differs by case only, because the filesystem can be
+// case-insensitive, like on macOS and Windows.
+func (self *Loader) cachedExists(dir, fname string) (exists, ok bool) {
+	switch self.filer.(type) {
+	case stdFiler, fsFiler:
+	default:
+		return false, false
+	}
+
+	if strings.ContainsRune(fname, '/') ||
+		strings.ContainsRune(fname, filepath.Separator) {
+		return false, false
+	}
+
+	entries, err := self.readDir(cmp.Or(dir, "."))
+	if err != nil {
+		return false, false
+	}
+
+	i, found := slices.BinarySearchFunc(entries, fname,
+		func(e fs.DirEntry, name string) int {
+			return strings.Compare(e.Name(), name)
+		})
+	if !found {
+		return false, !slices.ContainsFunc(entries, func(e fs.DirEntry) bool {
+			return strings.EqualFold(e.Name(), fname)
+		})
+	}
+	return true, entries[i].Type()&fs.ModeSymlink == 0
+}
@@ -0,0 +1,100 @@
+package dotenv
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWalkInfo_Entries(t *testing.T) {
+	dir := valueNoError[string](t)(filepath.EvalSymlinks(t.TempDir()))
+	wsDir := filepath.Join(dir, "ws")
+	subDir := filepath.Join(wsDir, "sub")
+	require.NoError(t, os.MkdirAll(subDir, 0o700))
+	require.NoError(t, os.WriteFile(filepath.Join(wsDir, "app.workspace"), nil,
+		0o600))
+	changeDir(t, subDir)
+
+	dirs := make(map[string][]string)
+	env := New().WithoutSetenv().WithRootFiles("*.workspace").WithWalkCallback(
+		func(info WalkInfo) (bool, error) {
+			entries, err := info.Entries()
+			if err != nil {
+				return false, err
+			}
+			cached := valueNoError[[]fs.DirEntry](t)(info.Entries())
+			if len(entries) != 0 {
+				assert.Same(t, &entries[0], &cached[0])
+			}
+
+			names := []string{}
+			for _, e := range entries {
+				names = append(names, e.Name())
+			}
+			dirs[info.Path] = names
+			return false, nil
+		})
+	require.NoError(t, env.Load())
+
+	assert.Equal(t, map[string][]string{
+		subDir: {},
+		wsDir:  {"app.workspace", "sub"},
+	}, dirs)
+	assert.Equal(t, Stop{
+		Reason: StopRootFile, Dir: wsDir, RootFile: "app.workspace",
+	}, env.Result().Stop)
+	assert.Equal(t, wsDir, env.dirCache.dir)
+
+	entries, err := WalkInfo{}.Entries()
+	require.NoError(t, err)
+	assert.Nil(t, entries)
+}
+
+func TestLoader_cachedExists(t *testing.T) {
+	dir := valueNoError[string](t)(filepath.EvalSymlinks(t.TempDir()))
+	subDir := filepath.Join(dir, "sub")
+	require.NoError(t, os.MkdirAll(subDir, 0o700))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, ".env"),
+		[]byte("TEST_VAR1=root\n"), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "go.mod"), nil, 0o600))
+	require.NoError(t, os.Symlink("not-exists", filepath.Join(subDir, ".env")))
+	changeDir(t, subDir)
+	restoreEnvVars(t)
+
+	env := New().WithoutSetenv()
+	require.NoError(t, env.Load())
+	assert.Equal(t, "root", env.Getenv(allEnvVars[0]))
+
+	stats := env.Result().Stats
+	assert.Equal(t, 2, stats.DirsVisited)
+	assert.Equal(t, 2, stats.ReadDirCalls)
+	assert.Equal(t, 1, stats.StatCalls, "broken symlink")
+
+	exists, ok := env.cachedExists(dir, "sub/.env")
+	assert.False(t, exists)
+	assert.False(t, ok)
+
+	caseDir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(caseDir, ".ENV"), nil, 0o600))
+	exists, ok = New().cachedExists(caseDir, ".env")
+	assert.False(t, exists)
+	assert.False(t, ok, "differs by case only")
+	exists, ok = New().cachedExists(caseDir, ".env.local")
+	assert.False(t, exists)
+	assert.True(t, ok)
+
+	env = New(WithFiler(filerFunc(os.Stat))).WithoutSetenv()
+	require.NoError(t, env.Load())
+	assert.Zero(t, env.Result().Stats.ReadDirCalls)
+	assert.Positive(t, env.Result().Stats.StatCalls)
+}
+
+type filerFunc func(name string) (os.FileInfo, error)
+
+func (self filerFunc) Stat(name string) (os.FileInfo, error) {
+	return self(name)
+}
@@ -0,0 +1,194 @@
+package dotenv
+
+import (
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+	"text/tabwriter"
+)
+
+// DocsFormat is a format of documentation, generated by [WriteDocs].
+type DocsFormat int
+
+const (
+	// DocsMarkdown is a Markdown table.
+	DocsMarkdown DocsFormat = iota
+
+	// DocsText is a plain text table.
+	DocsText
+)
+
+// DocsEntry documents an env var, declared by a schema. See [SchemaDocs].
+type DocsEntry struct {
+	// Key is a name of env var. Keys of maps end with separator and "*", like
+	// "LABELS__*".
+	Key string
+
+	// Type is a Go type of field.
+	Type string
+
+	// Default is a current value of field in schema, if it isn't zero. [Decode]
+	// keeps it, if env var isn't defined.
+	Default string
+
+	// Required is true if field has tag `required:"true"`.
+	Required bool
+
+	// Description is a value of tag "desc" of field.
+	Description string
+}
+
+// SchemaDocs returns documentation of all env vars, declared by schema, which
+// is a struct or a pointer to struct, like [Decode] expects, and opts are the
+// same as for [Decode]. Besides of "env" tag it understands tags, which are
+// used for documentation only:
+//
+//	cfg := struct {
+//		DatabaseURL string `env:"DATABASE_URL" required:"true" desc:"Postgres DSN"`
+//		Port        int    `desc:"HTTP port"`
+//	}{Port: 8080}
+//
+// Current values of fields are defaults.
+func SchemaDocs(schema any, opts ...DecodeOption) ([]DocsEntry, error) {
+	d := decoder{sep: DefaultSeparator}
+	for _, opt := range opts {
+		opt(&d)
+	}
+
+	rv := reflect.ValueOf(schema)
+	if rv.Kind() == reflect.Pointer && !rv.IsNil() {
+		rv = rv.Elem()
+	}
+	if rv.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("schema %T: %w", schema, ErrNotStructPtr)
+	}
+
+	var entries []DocsEntry
+	docsStruct(&entries, rv, "", d.sep, make(map[reflect.Type]bool))
+	return entries, nil
+}
+
+// docsStruct appends documentation of fields of struct rv to entries. visiting
+// contains struct types on current path, so self-referential types don't
+// recurse forever.
+func docsStruct(entries *[]DocsEntry, rv reflect.Value, prefix, sep string,
+	visiting map[reflect.Type]bool,
+) {
+	rt := rv.Type()
+	if visiting[rt] {
+		return
+	}
+	visiting[rt] = true
+	defer delete(visiting, rt)
+
+	for i := range rt.NumField() {
+		field := rt.Field(i)
+		name, ok := fieldKey(field)
+		if !ok {
+			continue
+		}
+
+		fv := rv.Field(i)
+		for fv.Kind() == reflect.Pointer {
+			if fv.IsNil() {
+				fv = reflect.Zero(fv.Type().Elem())
+			} else {
+				fv = fv.Elem()
+			}
+		}
+
+		key := prefix + name
+		if fv.Kind() == reflect.Struct && !isTextValue(fv.Type()) {
+			docsStruct(entries, fv, key+sep, sep, visiting)
+			continue
+		} else if fv.Kind() == reflect.Map {
+			key += sep + "*"
+		}
+
+		*entries = append(*entries, DocsEntry{
+			Key:         key,
+			Type:        field.Type.String(),
+			Default:     docsDefault(fv),
+			Required:    field.Tag.Get("required") == "true",
+			Description: field.Tag.Get("desc"),
+		})
+	}
+}
+
+// docsDefault returns rv as a string or empty string, if it's zero.
+func docsDefault(rv reflect.Value) string {
+	if rv.IsZero() {
+		return ""
+	} else if rv.CanAddr() {
+		if s, ok := rv.Addr().Interface().(fmt.Stringer); ok {
+			return s.String()
+		}
+	}
+	return fmt.Sprint(rv.Interface())
+}
+
+// WriteDocs writes documentation of all env vars, declared by schema, into w in
+// format. See [SchemaDocs] for details about schema and opts. So the env
+// contract is always documented from code.
+func WriteDocs(w io.Writer, schema any, format DocsFormat,
+	opts ...DecodeOption,
+) error {
+	entries, err := SchemaDocs(schema, opts...)
+	if err != nil {
+		return err
+	}
+
+	switch format {
+	case DocsMarkdown:
+		err = writeDocsMarkdown(w, entries)
+	case DocsText:
+		err = writeDocsText(w, entries)
+	default:
+		err = fmt.Errorf("unknown docs format %v", int(format))
+	}
+
+	if err != nil {
+		return fmt.Errorf("write docs: %w", err)
+	}
+	return nil
+}
+
+func writeDocsMarkdown(w io.Writer, entries []DocsEntry) error {
+	var sb strings.Builder
+	sb.WriteString("| Name | Type | Default | Required | Description |\n")
+	sb.WriteString("| --- | --- | --- | --- | --- |\n")
+	for _, e := range entries {
+		fmt.Fprintf(&sb, "| `%v` | `%v` | %v | %v | %v |\n", e.Key, e.Type,
+			markdownCell(e.Default), docsYesNo(e.Required),
+			markdownCell(e.Description))
+	}
+	_, err := io.WriteString(w, sb.String())
+	return err //nolint:wrapcheck // wrapped by WriteDocs
+}
+
+func writeDocsText(w io.Writer, entries []DocsEntry) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "NAME\tTYPE\tDEFAULT\tREQUIRED\tDESCRIPTION")
+	for _, e := range entries {
+		fmt.Fprintf(tw, "%v\t%v\t%v\t%v\t%v\n", e.Key, e.Type, e.Default,
+			docsYesNo(e.Required), e.Description)
+	}
+	return tw.Flush() //nolint:wrapcheck // wrapped by WriteDocs
+}
+
+// markdownCell escapes s for a cell of Markdown table.
+func markdownCell(s string) string {
+	if s == "" {
+		return ""
+	}
+	s = strings.ReplaceAll(s, "|", `\|`)
+	return strings.ReplaceAll(s, "\n", "<br>")
+}
+
+func docsYesNo(b bool) string {
+	if b {
+		return "yes"
+	}
+	return "no"
+}
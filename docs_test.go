@@ -0,0 +1,96 @@
+package dotenv
+
+import (
+	"bytes"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type docsConfig struct {
+	DatabaseURL string `env:"DATABASE_URL" required:"true" desc:"Postgres DSN"`
+	Port        int    `desc:"HTTP port | TCP"`
+	Timeout     time.Duration
+	Endpoint    url.URL
+	Password    Secret
+	Skipped     string `env:"-"`
+	Cache       *struct {
+		Size ByteSize
+	}
+	Labels  map[string]string
+	private string
+}
+
+func TestSchemaDocs(t *testing.T) {
+	cfg := docsConfig{
+		Port:     8080,
+		Timeout:  time.Minute,
+		Endpoint: url.URL{Scheme: "https", Host: "example.com"},
+		Password: "secret",
+	}
+
+	entries, err := SchemaDocs(&cfg)
+	require.NoError(t, err)
+	assert.Equal(t, []DocsEntry{
+		{
+			Key:         "DATABASE_URL",
+			Type:        "string",
+			Required:    true,
+			Description: "Postgres DSN",
+		},
+		{
+			Key: "PORT", Type: "int", Default: "8080",
+			Description: "HTTP port | TCP",
+		},
+		{Key: "TIMEOUT", Type: "time.Duration", Default: "1m0s"},
+		{Key: "ENDPOINT", Type: "url.URL", Default: "https://example.com"},
+		{Key: "PASSWORD", Type: "dotenv.Secret", Default: "***"},
+		{Key: "CACHE__SIZE", Type: "dotenv.ByteSize"},
+		{Key: "LABELS__*", Type: "map[string]string"},
+	}, entries)
+
+	_, err = SchemaDocs("not a struct")
+	require.ErrorIs(t, err, ErrNotStructPtr)
+}
+
+func TestSchemaDocs_recursive(t *testing.T) {
+	type node struct {
+		Name string `desc:"node name"`
+		Next *node
+	}
+
+	entries, err := SchemaDocs(&node{Name: "a", Next: &node{Name: "b"}})
+	require.NoError(t, err)
+	assert.Equal(t, []DocsEntry{
+		{Key: "NAME", Type: "string", Default: "a", Description: "node name"},
+	}, entries)
+}
+
+func TestWriteDocs(t *testing.T) {
+	cfg := struct {
+		DatabaseURL string `env:"DATABASE_URL" required:"true" desc:"Postgres DSN"`
+		Port        int    `desc:"HTTP port | TCP"`
+	}{Port: 8080}
+
+	var buf bytes.Buffer
+	require.NoError(t, WriteDocs(&buf, &cfg, DocsMarkdown))
+	assert.Equal(t, "| Name | Type | Default | Required | Description |\n"+
+		"| --- | --- | --- | --- | --- |\n"+
+		"| `DATABASE_URL` | `string` |  | yes | Postgres DSN |\n"+
+		"| `PORT` | `int` | 8080 | no | HTTP port \\| TCP |\n",
+		buf.String())
+
+	buf.Reset()
+	require.NoError(t, WriteDocs(&buf, cfg, DocsText))
+	assert.Equal(t,
+		"NAME          TYPE    DEFAULT  REQUIRED  DESCRIPTION\n"+
+			"DATABASE_URL  string           yes       Postgres DSN\n"+
+			"PORT          int     8080     no        HTTP port | TCP\n",
+		buf.String())
+
+	require.Error(t, WriteDocs(&buf, &cfg, DocsFormat(100)))
+	require.ErrorIs(t, WriteDocs(&buf, nil, DocsText), ErrNotStructPtr)
+}
@@ -9,14 +9,43 @@
 package dotenv
 
 import (
+	"cmp"
+	"context"
+	"crypto/cipher"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"io/fs"
+	"iter"
+	"log/slog"
+	"maps"
 	"os"
 	"path/filepath"
+	"slices"
+	"strings"
+	"time"
 
 	"github.com/joho/godotenv"
 )
 
+// DefaultMaxDirs is a default limit of dirs [Loader.Load] can visit, see
+// [Loader.WithMaxDirs].
+const DefaultMaxDirs = 256
+
+// ErrDepthExceeded returned by [Loader.Load] if it visited too many dirs or
+// detected a loop.
+var ErrDepthExceeded = errors.New("max number of dirs exceeded")
+
+// Overload loads .env files using default [Loader], like [Load] does, but
+// values of .env files override existing env vars. See [Loader.Overload].
+func Overload(callbacks ...func() error) error {
+	return New().Overload(callbacks...)
+}
+
+// ErrSlowLoad is a warning of [Loader.Load], if it exceeded duration,
+// configured by [Loader.WithMaxLoadDuration].
+var ErrSlowLoad = errors.New("load exceeded its budget")
+
 // Load loads .env files using default [Loader]. See [Loader.Load] for details
 // about callbacks.
 func Load(callbacks ...func() error) error {
@@ -42,8 +71,13 @@ func (self stdFiler) Stat(name string) (os.FileInfo, error) {
 // Creation time options can be changed by opts.
 func New(opts ...Option) *Loader {
 	l := &Loader{
-		rootDir:   string(filepath.Separator),
-		rootFiles: []string{"go.mod"},
+		procEnv:    stdProcessEnv{},
+		setenvFn:   os.Setenv,
+		clock:      stdClock{},
+		maxDirs:    DefaultMaxDirs,
+		rootDir:    string(filepath.Separator),
+		rootFiles:  []string{"go.mod"},
+		ignoreFile: ".dotenvignore",
 	}
 
 	for _, opt := range opts {
@@ -63,6 +97,72 @@ type Option func(l *Loader)
 // interface.
 func WithFiler(f Filer) Option { return func(l *Loader) { l.filer = f } }
 
+// ProcessEnv implements access to the process environment. See
+// [WithProcessEnv].
+type ProcessEnv interface {
+	// LookupEnv returns value of env var, see [os.LookupEnv].
+	LookupEnv(key string) (string, bool)
+
+	// Setenv sets value of env var, see [os.Setenv].
+	Setenv(key, value string) error
+
+	// Unsetenv removes env var, see [os.Unsetenv].
+	Unsetenv(key string) error
+
+	// Environ returns env vars in the form "key=value", see [os.Environ].
+	Environ() []string
+}
+
+type stdProcessEnv struct{}
+
+func (self stdProcessEnv) LookupEnv(key string) (string, bool) {
+	return os.LookupEnv(key)
+}
+
+func (self stdProcessEnv) Setenv(key, value string) error {
+	return os.Setenv(key, value) //nolint:wrapcheck // return it as is
+}
+
+func (self stdProcessEnv) Unsetenv(key string) error {
+	return os.Unsetenv(key) //nolint:wrapcheck // return it as is
+}
+
+func (self stdProcessEnv) Environ() []string { return os.Environ() }
+
+// mapProcessEnv implements [ProcessEnv] by a map.
+type mapProcessEnv map[string]string
+
+func (self mapProcessEnv) LookupEnv(key string) (string, bool) {
+	v, ok := self[key]
+	return v, ok
+}
+
+func (self mapProcessEnv) Setenv(key, value string) error {
+	self[key] = value
+	return nil
+}
+
+func (self mapProcessEnv) Unsetenv(key string) error {
+	delete(self, key)
+	return nil
+}
+
+func (self mapProcessEnv) Environ() []string {
+	environ := make([]string, 0, len(self))
+	for k, v := range self {
+		environ = append(environ, k+"="+v)
+	}
+	return environ
+}
+
+// WithProcessEnv configures [Loader] with custom implementation of
+// [ProcessEnv] interface, instead of the process environment, so tests can be
+// deterministic without touching real env vars. Options after it, like
+// [WithEnvVarName], use it too.
+func WithProcessEnv(p ProcessEnv) Option {
+	return func(l *Loader) { l.procEnv, l.setenvFn = p, p.Setenv }
+}
+
 // Loader is a loader of .env files. Don't create it directly, use [New]
 // instead.
 type Loader struct {
@@ -74,18 +174,270 @@ type Loader struct {
 	// dirs. 0 means not configured.
 	lookupDepth int
 
+	// maxDirs is a hard limit of visited dirs. 0 means unlimited.
+	maxDirs int
+
 	// rootCb is a function, which returns should we stop at current dir or go up.
-	rootCb func(path string) (bool, error)
+	rootCb func(info WalkInfo) (bool, error)
+
+	// walkDepth is depth of current dir of lookup.
+	walkDepth int
 
 	// rootDir is a dir to stop and don't go up
 	rootDir string
 
+	// clampRootDir clamps rootDir to the nearest common ancestor of it and
+	// start dir.
+	clampRootDir bool
+
+	// walkRoot is rootDir of current lookup, see walkRootDir.
+	walkRoot string
+
+	// fallbackStartDir is a dir to start lookup, if current dir is unknown.
+	fallbackStartDir string
+
 	// rootFiles contains list of file names for marking root dir. If current or
 	// any parent dir has any of file from this list, we'll stop at that dir.
 	rootFiles []string
 
+	// ignoreFile is a name of file, which marks dir to skip its .env files.
+	ignoreFile string
+
 	// filer contains an interface to OS functions
 	filer Filer
+
+	// fsys is a filesystem, configured by WithFS, instead of the OS one.
+	fsys fs.FS
+
+	// preset is a preset, configured by WithPreset.
+	preset Preset
+
+	// withoutLocal excludes .local files from the cascade.
+	withoutLocal bool
+
+	// namespace is a name of library, which .env files are loaded, if
+	// namespaced is true. See Namespace.
+	namespace  string
+	namespaced bool
+
+	// fileName is a base name of .env files instead of ".env", see
+	// WithFileName.
+	fileName string
+
+	// files is a list of .env files instead of the cascade, see WithFiles.
+	files []string
+
+	// fileOrder returns list of .env files for given name of environment, see
+	// WithFileOrder.
+	fileOrder func(envName string) []string
+
+	// envFilesFn returns list of .env files for given name of environment,
+	// instead of default list.
+	envFilesFn func(envName string) []string
+
+	// encodedValues enables decoding of values with "base64:" and "hex:"
+	// prefixes.
+	encodedValues bool
+
+	// keyProvider provides a key for decryption of encrypted values.
+	keyProvider KeyProvider
+
+	// exportKP provides a key for encryption of exportSecrets by snapshots and
+	// exports, see WithExportEncryption.
+	exportKP      KeyProvider
+	exportSecrets []string
+
+	// aead decrypts encrypted values, it's created using key from keyProvider.
+	aead cipher.AEAD
+
+	// continueOnError enables loading of next .env files after error in
+	// previous one.
+	continueOnError bool
+
+	// ignorePermission enables stopping at dir, which isn't readable, instead of
+	// returning an error.
+	ignorePermission bool
+
+	// dirChangeHook is called if current dir changed since previous Load.
+	dirChangeHook func(prevDir, curDir string)
+
+	// lastDir is current dir of previous Load.
+	lastDir string
+
+	// maxLoadDuration is a budget of Load.
+	maxLoadDuration time.Duration
+
+	// warningHook is called with every warning after every Load.
+	warningHook func(warn error)
+
+	// middlewares wrap every Load, the first one is the outermost.
+	middlewares []Middleware
+
+	// statsHook is called with statistics after every Load.
+	statsHook func(stats Stats)
+
+	// procEnv is an access to the process environment, see WithProcessEnv.
+	procEnv ProcessEnv
+
+	// setenvFn sets env var, it's [os.Setenv] by default.
+	setenvFn func(key, value string) error
+
+	// override makes .env files win over the process environment.
+	override bool
+
+	// serializeSetenv applies env vars under setenvMu.
+	serializeSetenv bool
+
+	// noSetenv disables setting of env vars, they are kept in vars instead.
+	noSetenv bool
+
+	// hermetic hides the process environment, except hermeticAllow and
+	// essential env vars.
+	hermetic      bool
+	hermeticAllow []string
+
+	// vars contains env vars of last Load, which can't be set by setenvFn or
+	// weren't set because of noSetenv. It's replaced as a whole under setenvMu,
+	// so concurrent readers never see it half-built.
+	vars map[string]string
+
+	// pending collects vars of current Load, till finishLoad publishes it as
+	// vars.
+	pending map[string]string
+
+	// stats collects statistics of current Load.
+	stats Stats
+
+	// stop describes why lookup of current Load stopped.
+	stop Stop
+
+	// warnings collects warnings of current Load.
+	warnings []error
+
+	// envDir is a dir with .env files, found by current Load. Empty string
+	// means current dir.
+	envDir string
+
+	// featureFlags enables loading of .flags files into flags.
+	featureFlags bool
+	flags        map[string]string
+
+	// loaded contains effective values of all env vars, defined by .env files
+	// of last Load.
+	loaded map[string]string
+
+	// sources contains name of the first .env file, which defined env var, for
+	// every env var of last Load.
+	sources map[string]string
+
+	// required contains keys, which must be defined after Load.
+	required []string
+
+	// requiredFiles contains names of .env files, which must exist.
+	requiredFiles []string
+
+	// secretsEnabled enables loading of .env files with secrets.
+	secretsEnabled bool
+
+	// envrc enables .env files from .envrc.
+	envrc bool
+
+	// transcoding enables transcoding of not UTF-8 .env files.
+	transcoding bool
+
+	// checksums contains pinned checksums of .env files by their base names.
+	checksums map[string]string
+
+	// schema contains keys, declared by schema of WithDisallowUnknown.
+	schema *schemaKeys
+
+	// schemaErr is an error from WithDisallowUnknown.
+	schemaErr error
+
+	// archiveErr is an error from WithArchiveSource.
+	archiveErr error
+
+	// snapshotFallback is a path of snapshot file, which is loaded if .env
+	// files can't be loaded.
+	snapshotFallback string
+
+	// renames maps old names of keys to new ones.
+	renames map[string]string
+
+	// keepRenamed keeps old names of renamed keys.
+	keepRenamed bool
+
+	// maxValueLen is max length of values.
+	maxValueLen int
+
+	// singleLine contains patterns of keys, which values must be single line.
+	singleLine []string
+
+	// deprecated maps deprecated keys to messages about them.
+	deprecated map[string]string
+
+	// values contains env vars of WithValues.
+	values map[string]string
+
+	// priorities contains priorities of .env files by their base names.
+	priorities map[string]int
+
+	// skipped contains .env files, skipped by last Load.
+	skipped []SkippedFile
+
+	// provenance contains provenance of every env var of last Load.
+	provenance map[string]*Provenance
+
+	// winners contains source of effective value of every env var, defined by
+	// .env files of last Load.
+	winners map[string]SourceRef
+
+	// conflictResolver resolves values of env vars, defined by more than one
+	// source.
+	conflictResolver ConflictResolver
+
+	// precedence contains resolution order of sources of last Load.
+	precedence []SourceRef
+
+	// trace writes trace events, see WithTraceWriter.
+	trace *json.Encoder
+
+	// record enables recording of every Load into recording.
+	record    bool
+	recording *Recording
+
+	// ctx is a context of LoadContext and lastPath is the last path, touched
+	// by lookup with it.
+	ctx      context.Context //nolint:containedctx // it's for LoadContext only
+	lastPath string
+
+	// pollInterval is an interval of polling by Watch, see WithPollInterval.
+	pollInterval time.Duration
+
+	// reloadHook is called after every reload of Watch.
+	reloadHook func(changes Changes)
+
+	// clock implements timers of Watch, see WithClock.
+	clock Clock
+
+	// logger receives debug records of lookup, see WithLogger.
+	logger *slog.Logger
+
+	// dirCache contains entries of the last read dir, see readDir.
+	dirCache *dirEntries
+
+	// mustFind makes Load fail, if no .env files found, see WithRequired.
+	mustFind bool
+
+	// strictOwner refuses files of other users, see WithStrictOwnership.
+	strictOwner bool
+
+	// startAt is a dir to start lookup instead of current dir, see ReplayLoad
+	// and For.
+	startAt string
+
+	// result contains result of last Load.
+	result *Result
 }
 
 // WithDepth configures [Loader.Load] don't go up deeper and stop searching for
@@ -96,6 +448,15 @@ func (self *Loader) WithDepth(n int) *Loader {
 	return self
 }
 
+// WithMaxDirs configures a hard limit of dirs [Loader.Load] can visit, unlike
+// [Loader.WithDepth] it doesn't stop silently, but returns an error wrapping
+// [ErrDepthExceeded]. It protects from walking effectively forever on exotic
+// filesystems. By default it's [DefaultMaxDirs] and n <= 0 means unlimited.
+func (self *Loader) WithMaxDirs(n int) *Loader {
+	self.maxDirs = n
+	return self
+}
+
 // WithEnvVarName reads name of current environment from s environment variable
 // and configures [Loader.Load] for searching and loading of .env.CURENV*
 // files. For instance with s == "production" it'll search also for
@@ -111,7 +472,7 @@ func (self *Loader) WithDepth(n int) *Loader {
 // So if "ENV" environment variable contains "test", next call to [Loader.Load]
 // will try to load ".env.test*" files. See [Loader.Load] for details.
 func (self *Loader) WithEnvVarName(s string) *Loader {
-	if v, ok := os.LookupEnv(s); ok {
+	if v, ok := self.procEnv.LookupEnv(s); ok {
 		self.envSuffix = v
 	}
 	return self
@@ -124,6 +485,71 @@ func (self *Loader) WithEnvSuffix(s string) *Loader {
 	return self
 }
 
+// WithFileName configures [Loader.Load] to search for .env files with base name
+// fname instead of ".env", like "app.env", "app.env.local",
+// "app.env.production" and so on, for services with non-standard names of
+// files:
+//
+//	env := dotenv.New().WithFileName("app.env").WithEnvSuffix("production")
+//
+// It applies to files with secrets and namespaces too, but not to presets,
+// which define their own names of files, see [Loader.WithPreset]. Empty fname
+// restores default ".env".
+func (self *Loader) WithFileName(fname string) *Loader {
+	self.fileName = fname
+	return self
+}
+
+// WithFiles configures [Loader.Load] to search for .env files with names from
+// fnames, in order of precedence, instead of the cascade of
+// [Loader.WithEnvSuffix], [Loader.WithFileName] and [Loader.WithPreset]:
+//
+//	env := dotenv.New().WithFiles("secrets.env", "defaults.env")
+//
+// Lookup stops at the first dir with any of them, like it always does.
+// [Loader.WithoutLocalFiles] and [Loader.WithSecretsEnabled] still apply. No
+// fnames restores the cascade.
+func (self *Loader) WithFiles(fnames ...string) *Loader {
+	if len(fnames) == 0 {
+		self.files = nil
+	} else {
+		self.files = slices.Clone(fnames)
+	}
+	return self
+}
+
+// WithFileOrder configures [Loader.Load] to search for .env files, which fn
+// returns for name of environment, in order of precedence, instead of the
+// default cascade. So it can reorder or extend the cascade, like frameworks
+// disagreeing on these rules do:
+//
+//	env := dotenv.New().WithFileOrder(func(envName string) []string {
+//		if envName == "" {
+//			return []string{".env.local", ".env"}
+//		}
+//		return []string{
+//			".env.local", ".env." + envName + ".local",
+//			".env." + envName, ".env",
+//		}
+//	})
+//
+// fn gets name of environment, configured by [Loader.WithEnvSuffix] or
+// [Loader.WithEnvVarName]. It takes priority over [Loader.WithPreset] and
+// [Loader.WithFileName], but not over [Loader.WithFiles]. Nil fn restores the
+// default cascade.
+func (self *Loader) WithFileOrder(fn func(envName string) []string) *Loader {
+	self.fileOrder = fn
+	return self
+}
+
+// WithoutLocalFiles configures [Loader.Load] to skip all .local files of the
+// cascade, like ".env.local" and ".env.test.local", so results don't depend on
+// overrides of a developer machine. It's useful for tests and CI.
+func (self *Loader) WithoutLocalFiles() *Loader {
+	self.withoutLocal = true
+	return self
+}
+
 // WithRootDir configures [Loader.Load] to stop at path dir and don't go up.
 func (self *Loader) WithRootDir(path string) *Loader {
 	if absPath, err := filepath.Abs(path); err == nil {
@@ -132,13 +558,144 @@ func (self *Loader) WithRootDir(path string) *Loader {
 	return self
 }
 
+// WithFallbackStartDir configures [Loader.Load] to start lookup at path dir, if
+// current dir can't be determined, because it was deleted for instance.
+// Without it Load returns an error in this case. path can be dir of executable
+// or user home dir:
+//
+//	exe, err := os.Executable()
+//	if err != nil {
+//		return err
+//	}
+//	env := dotenv.New().WithFallbackStartDir(filepath.Dir(exe))
+//
+// Load adds a warning into [Result.Warnings], if it starts at path. path must
+// be absolute, if current dir is unknown during configuration.
+func (self *Loader) WithFallbackStartDir(path string) *Loader {
+	if absPath, err := filepath.Abs(path); err == nil {
+		path = absPath
+	}
+	self.fallbackStartDir = path
+	return self
+}
+
 // WithRootFiles configures [Loader.Load] to stop at current dir or any parent
-// dir, which contains any of file (or dir) with name from fnames list.
+// dir, which contains any of file (or dir) with name from fnames list. Any name
+// can be a glob pattern of [filepath.Match], because monorepo markers vary and
+// can't always be enumerated exactly:
+//
+//	env := dotenv.New().WithRootFiles("*.workspace", "WORKSPACE*",
+//		"pnpm-workspace.yaml")
+//
+// Patterns are matched against entries of dir, which are read from the OS
+// filesystem or [Loader.WithFS], not using [Filer].
 func (self *Loader) WithRootFiles(fnames ...string) *Loader {
 	self.rootFiles = fnames
 	return self
 }
 
+// WithIgnoreFile configures [Loader.Load] to skip .env files in any dir, which
+// contains a file (or dir) named fname, but continue to its parent dir. By
+// default it's ".dotenvignore", so an empty ".dotenvignore" file excludes
+// vendored or example dirs from accidental pickup of their .env files. Empty
+// fname disables this check.
+func (self *Loader) WithIgnoreFile(fname string) *Loader {
+	self.ignoreFile = fname
+	return self
+}
+
+// WithEncodedValues configures [Loader.Load] to decode values with known
+// prefixes before setting them:
+//
+//	KEY1=base64:aGVsbG8=
+//	KEY2=hex:68656c6c6f
+//
+// Both of them set "hello". Values without these prefixes are set as is.
+func (self *Loader) WithEncodedValues() *Loader {
+	self.encodedValues = true
+	return self
+}
+
+// WithContinueOnError configures [Loader.Load] to continue loading of next
+// .env files, if it got an error from previous one. For instance, a parse error
+// in ".env.local" doesn't prevent loading of ".env". All errors are joined
+// together and returned after loading of all files, without calling of
+// callbacks.
+func (self *Loader) WithContinueOnError() *Loader {
+	self.continueOnError = true
+	return self
+}
+
+// WithIgnorePermissionErrors configures [Loader.Load] to stop at a dir, which
+// it can't check because of [os.ErrPermission], instead of failing. It's
+// common in locked-down home dirs or containers. Load keeps that error in
+// [Result.Warnings] and continues like nothing was found in that dir.
+func (self *Loader) WithIgnorePermissionErrors() *Loader {
+	self.ignorePermission = true
+	return self
+}
+
+// WithDirChangeHook configures [Loader.Load] to call fn, if current dir of the
+// process changed since previous Load, which is common in interactive tools.
+// prevDir is current dir of previous Load and curDir is current dir of this
+// Load. So callers know their config root probably moved. Load doesn't cache
+// discovery of .env files and always looks for them from current dir.
+func (self *Loader) WithDirChangeHook(fn func(prevDir, curDir string),
+) *Loader {
+	self.dirChangeHook = fn
+	return self
+}
+
+// checkDirChange calls dirChangeHook and writes [TraceDirChange] event, if
+// current dir changed since previous Load.
+func (self *Loader) checkDirChange() {
+	if self.dirChangeHook == nil && self.trace == nil {
+		return
+	}
+
+	curDir, err := os.Getwd()
+	if err != nil {
+		return
+	}
+
+	prevDir := self.lastDir
+	self.lastDir = curDir
+	if prevDir == "" || prevDir == curDir {
+		return
+	}
+
+	self.traceEvent(TraceEvent{
+		Event: TraceDirChange, Dir: curDir, PrevDir: prevDir,
+	})
+	if self.dirChangeHook != nil {
+		self.dirChangeHook(prevDir, curDir)
+	}
+}
+
+// WithMaxLoadDuration configures [Loader.Load] to add a warning wrapping
+// [ErrSlowLoad] into [Result.Warnings], if Load takes longer than d. It helps
+// to catch regressions from new sources or slow mounts. See also
+// [Loader.WithWarningHook].
+func (self *Loader) WithMaxLoadDuration(d time.Duration) *Loader {
+	self.maxLoadDuration = d
+	return self
+}
+
+// WithWarningHook configures [Loader.Load] to call fn with every warning, after
+// every Load, successful or not. Warnings are problems, which didn't fail Load,
+// see [Result.Warnings].
+func (self *Loader) WithWarningHook(fn func(warn error)) *Loader {
+	self.warningHook = fn
+	return self
+}
+
+// WithStatsHook configures [Loader.Load] to call fn with statistics of every
+// Load, successful or not. It may be used for exporting them as metrics.
+func (self *Loader) WithStatsHook(fn func(stats Stats)) *Loader {
+	self.statsHook = fn
+	return self
+}
+
 // WithRootCallback configures [Loader.Load] to call fn function for every dir
 // it visits. It passes absolute path of current dir as path param and expects
 // two return values:
@@ -148,6 +705,51 @@ func (self *Loader) WithRootFiles(fnames ...string) *Loader {
 //
 // [Loader.FileExistsInDir] may be useful in here.
 func (self *Loader) WithRootCallback(fn func(path string) (bool, error),
+) *Loader {
+	if fn == nil {
+		self.rootCb = nil
+		return self
+	}
+	self.rootCb = func(info WalkInfo) (bool, error) { return fn(info.Path) }
+	return self
+}
+
+// WalkInfo describes state of lookup for callback of
+// [Loader.WithWalkCallback].
+type WalkInfo struct {
+	// Path is absolute path of current dir.
+	Path string
+
+	// Depth is depth of current dir: start dir has depth 1, its parent has
+	// depth 2 and so on.
+	Depth int
+
+	// DirsVisited is a number of dirs visited so far, including current one.
+	DirsVisited int
+
+	// StatCalls is a number of [Filer.Stat] calls so far.
+	StatCalls int
+
+	// ReadDirCalls is a number of reads of dirs so far.
+	ReadDirCalls int
+
+	// Skipped contains .env files, which lookup found so far, but skipped, see
+	// [SkippedFile].
+	Skipped []SkippedFile
+
+	loader *Loader
+}
+
+// WithWalkCallback works like [Loader.WithRootCallback], but fn gets state of
+// lookup, so it can implement depth or match aware stop logic, without
+// duplicating internal state of [Loader]:
+//
+//	env := dotenv.New().WithWalkCallback(func(info dotenv.WalkInfo) (bool, error) {
+//		return info.Depth >= 3 && len(info.Skipped) != 0, nil
+//	})
+//
+// It replaces callback of WithRootCallback and vice versa.
+func (self *Loader) WithWalkCallback(fn func(info WalkInfo) (bool, error),
 ) *Loader {
 	self.rootCb = fn
 	return self
@@ -165,6 +767,9 @@ func (self *Loader) WithRootCallback(fn func(path string) (bool, error),
 //  4. A callback function was configured by [Loader.WithRootCallback] and that
 //     function returned true for visited dir.
 //
+// Any dir with ignore marker, configured by [Loader.WithIgnoreFile], is
+// visited, but its .env files are skipped.
+//
 // If name of environment wasn't configured by [Loader.WithEnvVarName] or
 // [Loader.WithEnvSuffix], Load is looking for:
 //
@@ -179,11 +784,20 @@ func (self *Loader) WithRootCallback(fn func(path string) (bool, error),
 //  3. .env.production
 //  4. .env
 //
-// Load uses [godotenv.Load] and according to how it works any already defined
-// env variable can't be redefined by next .env file and has priority. So if
-// variable "A" defined in .env.local file, it can't be redefined by variable
-// "A" from .env file. Or if env variable "A" somehow defined before calling
-// Load, it keeps its value and can't be redefined by .env files.
+// Load works like [godotenv.Load] and according to how it works any already
+// defined env variable can't be redefined by next .env file and has priority.
+// So if variable "A" defined in .env.local file, it can't be redefined by
+// variable "A" from .env file. Or if env variable "A" somehow defined before
+// calling Load, it keeps its value and can't be redefined by .env files.
+//
+// The order is deterministic and it's guaranteed:
+//
+//  1. Files are applied one by one in order of the list above, so the first
+//     file, which defines a variable, wins.
+//  2. If the same file defines a variable more than once, the last definition
+//     wins.
+//  3. Variables of a file are decoded and applied in lexical order of their
+//     names, so errors and warnings are reported in that order too.
 //
 // After succesfull loading of .env file(s) it calls functions from cbs one by
 // one. It stops calling callbacks after first error. Here an example of using
@@ -202,17 +816,71 @@ func (self *Loader) WithRootCallback(fn func(path string) (bool, error),
 //		log.Fatalf("error loading .env files: %v", err)
 //	}
 //
+// Load runs middlewares, configured by [Loader.WithMiddleware], around all of
+// it, see [Middleware].
+//
 // [env]: https://github.com/caarlos0/env
 func (self *Loader) Load(callbacks ...func() error) error {
-	envs, err := self.lookupEnvFiles()
+	return self.runMiddlewares(func() error { return self.load(callbacks) })
+}
+
+// runMiddlewares runs load wrapped by configured middlewares.
+func (self *Loader) runMiddlewares(load LoadFunc) error {
+	for _, mw := range slices.Backward(self.middlewares) {
+		load = mw(load)
+	}
+	return load()
+}
+
+// load implements [Loader.Load] without middlewares.
+func (self *Loader) load(callbacks []func() error) error {
+	self.resetLoad()
+	defer self.finishStats(time.Now())
+	self.startRecording()
+	self.checkDirChange()
+
+	if err := self.Validate(); err != nil {
+		return err
+	}
+	self.renameEnv()
+
+	found, err := self.loadEnvFiles()
+	if self.snapshotFallback != "" && (err != nil || !found) {
+		err = self.loadSnapshot(err)
+	}
+	return self.finishLoad(err, callbacks)
+}
+
+// resetLoad resets state of previous Load.
+func (self *Loader) resetLoad() {
+	self.stats, self.stop, self.warnings = Stats{}, Stop{}, nil
+	self.aead, self.loaded = nil, make(map[string]string)
+	self.pending = nil
+	self.sources = make(map[string]string)
+	self.winners = make(map[string]SourceRef)
+	self.precedence = []SourceRef{{Kind: SourceEnv}}
+	if self.override {
+		self.precedence = self.precedence[:0]
+	}
+	self.provenance = make(map[string]*Provenance)
+	self.skipped, self.envDir, self.flags = nil, "", nil
+	self.lastPath, self.dirCache = "", nil
+}
+
+// finishLoad finishes Load, which loaded sources with error err: checks
+// required env vars and calls callbacks.
+func (self *Loader) finishLoad(err error, callbacks []func() error) error {
+	self.publishVars()
+	if self.override {
+		self.precedence = append(self.precedence, SourceRef{Kind: SourceEnv})
+	}
 	if err != nil {
 		return err
 	}
 
-	if len(envs) > 0 {
-		if err := godotenv.Load(envs...); err != nil {
-			return fmt.Errorf("can't load %v: %w", envs, err)
-		}
+	self.checkDeprecatedEnv()
+	if err := self.checkRequired(); err != nil {
+		return err
 	}
 
 	for _, cb := range callbacks {
@@ -224,20 +892,301 @@ func (self *Loader) Load(callbacks ...func() error) error {
 	return nil
 }
 
+// publishVars replaces vars of previous Load by vars of current one.
+func (self *Loader) publishVars() {
+	setenvMu.Lock()
+	defer setenvMu.Unlock()
+	self.vars = self.pending
+}
+
+// loadEnvFiles looks for .env files and loads them. It returns true if any of
+// .env files was found.
+func (self *Loader) loadEnvFiles() (bool, error) {
+	envs, err := self.lookupEnvFiles()
+	self.traceStop()
+	if err != nil {
+		return false, err
+	} else if err := self.checkRequiredFiles(envs); err != nil {
+		return false, err
+	} else if len(envs) == 0 && self.values == nil {
+		return false, nil
+	}
+
+	found := len(envs) != 0
+	if err := self.loadFiles(envs); err != nil {
+		return found, fmt.Errorf("can't load %v: %w", envs, err)
+	} else if found {
+		return true, self.loadFlagFiles()
+	}
+	return false, nil
+}
+
+// Result returns result of last [Loader.Load] or nil, if it wasn't called yet.
+func (self *Loader) Result() *Result { return self.result }
+
+// finishStats sets wall time of current Load, started at startTime, makes
+// result of it and calls configured stats hook.
+func (self *Loader) finishStats(startTime time.Time) {
+	self.stats.WallTime = time.Since(startTime)
+	if d := self.maxLoadDuration; d > 0 && self.stats.WallTime > d {
+		self.warnings = append(self.warnings, fmt.Errorf("%w: took %v, budget %v",
+			ErrSlowLoad, self.stats.WallTime, d))
+	}
+	self.result = &Result{
+		Stats:    self.stats,
+		Stop:     self.stop,
+		Warnings: self.warnings,
+		Skipped:  self.skipped,
+		Dir:      self.envDir,
+		Files:    self.resultFiles(),
+
+		provenance: self.provenanceList(),
+		fsys:       self.fsys,
+	}
+	self.result.SetKeys, self.result.ExistingKeys = self.resultKeys()
+	if self.recording != nil {
+		self.recording.Stop = self.stop
+	}
+	if self.warningHook != nil {
+		for _, warn := range self.warnings {
+			self.warningHook(warn)
+		}
+	}
+	if self.statsHook != nil {
+		self.statsHook(self.stats)
+	}
+}
+
+// loadFiles reads .env files from envs one by one and sets env vars, which
+// aren't defined yet, like [godotenv.Load] does.
+//
+// If it's configured by [Loader.WithContinueOnError], it continues with next
+// file after an error and returns all errors joined. The same physical file is
+// loaded once, see uniqueFiles.
+func (self *Loader) loadFiles(envs []string) error {
+	refs := make([]SourceRef, 0, len(envs)+1)
+	if self.values != nil {
+		refs = append(refs, SourceRef{Kind: SourceValues})
+	}
+	for _, fname := range self.uniqueFiles(envs) {
+		refs = append(refs, SourceRef{Kind: SourceFile, Name: fname})
+	}
+	self.sortByPriority(refs)
+
+	var errs []error
+	for _, ref := range refs {
+		if err := self.applySource(ref); err != nil {
+			if !self.continueOnError {
+				return err
+			}
+			if ref.Name != "" {
+				self.skipFile(ref.Name, err.Error())
+			} else {
+				self.skipFile(ref.String(), err.Error())
+			}
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// uniqueFiles returns envs without files, which are the same physical file as
+// any of previous ones, like a symlink .env to .env.local or a hardlink. Files,
+// which can't be checked, are kept as is. Removed files are recorded as
+// skipped.
+func (self *Loader) uniqueFiles(envs []string) []string {
+	if len(envs) < 2 || self.fsys != nil {
+		return envs
+	}
+
+	unique := make([]string, 0, len(envs))
+	infos := make([]os.FileInfo, 0, len(envs))
+	for _, fname := range envs {
+		fi, err := os.Stat(fname)
+		if err != nil {
+			unique = append(unique, fname)
+			continue
+		}
+
+		i := slices.IndexFunc(infos, func(prev os.FileInfo) bool {
+			return os.SameFile(prev, fi)
+		})
+		if i >= 0 {
+			self.skipFile(fname, "same file as "+unique[i])
+			continue
+		}
+		infos = append(infos, fi)
+		unique = append(unique, fname)
+	}
+	return unique
+}
+
+// loadFile reads .env file of ref and sets env vars, which aren't defined yet.
+func (self *Loader) loadFile(ref SourceRef) error {
+	vars, err := self.readSource(ref)
+	if err != nil {
+		return err
+	}
+	return self.loadVars(ref, vars)
+}
+
+// loadVars sets env vars of source ref, which aren't defined yet.
+func (self *Loader) loadVars(ref SourceRef, vars map[string]string) error {
+	fname := ref.Name
+	if fname == "" {
+		fname = ref.String()
+	}
+
+	self.renameKeys(fname, vars)
+	self.checkDeprecated(fname, vars)
+	if err := self.checkUnknownKeys(fname, vars); err != nil {
+		return err
+	} else if err := self.checkValues(fname, vars); err != nil {
+		return err
+	}
+
+	for _, k := range self.applyVars(ref, fname, vars) {
+		if err := self.resolveConflict(ref, k, vars[k]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// applyVars sets env vars of source ref, loaded from fname, which aren't
+// defined yet, and returns keys, which are defined already.
+func (self *Loader) applyVars(ref SourceRef, fname string,
+	vars map[string]string,
+) (conflicts []string) {
+	if self.serializeSetenv {
+		setenvMu.Lock()
+		defer setenvMu.Unlock()
+	}
+
+	lines := self.traceLines(ref)
+	for _, k := range slices.Sorted(maps.Keys(vars)) {
+		if _, ok := self.sources[k]; !ok {
+			self.sources[k] = fname
+		}
+		v, ok := self.lookupPending(k)
+		if self.override {
+			v, ok = self.loaded[k]
+		}
+		if !ok {
+			self.setenv(fname, k, vars[k])
+			self.loaded[k] = vars[k]
+			self.winners[k] = ref
+		} else if _, ok := self.loaded[k]; !ok {
+			self.loaded[k] = v
+			self.winners[k] = SourceRef{Kind: SourceEnv}
+		}
+		if ok {
+			conflicts = append(conflicts, k)
+		}
+		self.traceVar(fname, k, lines[k], !ok)
+		self.addProvenance(ref, k, !ok)
+	}
+	return conflicts
+}
+
+// readSource returns env vars of source ref.
+func (self *Loader) readSource(ref SourceRef) (map[string]string, error) {
+	if ref.Kind == SourceValues {
+		return maps.Clone(self.values), nil
+	}
+
+	startTime := time.Now()
+	vars, err := self.readFile(ref.Name)
+	self.stats.addFile(ref.Name, time.Since(startTime))
+	return vars, err
+}
+
+// readFile reads and parses .env file fname and returns its env vars, decoded
+// according to configuration.
+func (self *Loader) readFile(fname string) (map[string]string, error) {
+	if err := self.checkSecretsFile(fname); err != nil {
+		return nil, err
+	} else if err := self.checkOwner(fname); err != nil {
+		return nil, err
+	}
+
+	b, err := self.readFileBytes(fname)
+	if err != nil {
+		return nil, fmt.Errorf("read %v: %w", fname, err)
+	}
+	self.recordFile(fname, b)
+
+	if err := self.verifyChecksum(fname, b); err != nil {
+		return nil, err
+	}
+	return self.parseBytes(fname, b)
+}
+
+// parseBytes parses content b of .env file fname and returns its env vars,
+// decoded according to configuration.
+func (self *Loader) parseBytes(fname string, b []byte) (map[string]string,
+	error,
+) {
+	b, err := self.toUTF8(fname, b)
+	if err != nil {
+		return nil, err
+	}
+
+	vars, err := godotenv.UnmarshalBytes(b)
+	if err != nil {
+		return nil, fmt.Errorf("read %v: %w", fname, err)
+	}
+
+	for _, k := range slices.Sorted(maps.Keys(vars)) {
+		v := vars[k]
+		if self.encodedValues {
+			if v, err = decodeValue(v); err != nil {
+				return nil, fmt.Errorf("decode %v from %v: %w", k, fname, err)
+			}
+		}
+		if vars[k], err = self.decryptValue(k, v); err != nil {
+			return nil, fmt.Errorf("decrypt %v from %v: %w", k, fname, err)
+		}
+	}
+	return vars, nil
+}
+
 // FileExistsInDir checks if file named fname exists in dir named dirName and
 // returns true, if it exists, or false.
 //
 // May be useful in a callback, configured by [Loader.WithRootCallback].
 func (self *Loader) FileExistsInDir(dirName, fname string) (bool, error) {
+	if err := self.checkContext(); err != nil {
+		return false, err
+	}
+
+	name := fname
 	if dirName != "" {
 		fname = filepath.Join(dirName, fname)
 	}
 
-	if _, err := self.filer.Stat(fname); err == nil {
+	if exists, ok := self.cachedExists(dirName, name); ok {
+		var err error
+		if !exists {
+			err = fs.ErrNotExist
+		}
+		self.recordStat(fname, err)
+		self.logStat(fname, exists, nil)
+		return exists, nil
+	}
+
+	self.touchPath(fname)
+	self.stats.StatCalls++
+	_, err := self.filer.Stat(fname)
+	self.recordStat(fname, err)
+	if err == nil {
+		self.logStat(fname, true, nil)
 		return true, nil
 	} else if !errors.Is(err, os.ErrNotExist) {
+		self.logStat(fname, false, err)
 		return false, fmt.Errorf("can't stat file '%s': %w", fname, err)
 	}
+	self.logStat(fname, false, nil)
 
 	return false, nil
 }
@@ -249,21 +1198,42 @@ func (self *Loader) FileExistsInDir(dirName, fname string) (bool, error) {
 // paths. If they are in current dir, returned list will contain just their
 // names.
 func (self *Loader) lookupEnvFiles() ([]string, error) {
+	// Found dir can disappear before its .env files are collected, like
+	// ephemeral build dirs do. Retry once and stop after that.
+	for range 2 {
+		envs, found, err := self.findEnvFiles()
+		if err != nil || !found || len(envs) != 0 {
+			return envs, err
+		}
+	}
+
+	self.warnings = append(self.warnings,
+		fmt.Errorf("dir %v disappeared during lookup", self.stop.Dir))
+	self.stop.Reason = StopVanished
+	return nil, nil
+}
+
+// findEnvFiles looks for dir with .env files and returns all .env files in it.
+// It returns found == true and empty envs, if the dir disappeared after it was
+// found.
+func (self *Loader) findEnvFiles() ([]string, bool, error) {
 	envs := self.envFiles()
 
-	found, envDir, err := self.lookupEnvDir(envs)
+	found, envDir, envs, err := self.lookupEnvDir(envs)
 	if err != nil {
-		return nil, fmt.Errorf("got error looking for %v: %w", envs, err)
+		return nil, false, fmt.Errorf("got error looking for %v: %w", envs, err)
 	} else if !found {
-		return nil, nil
+		return nil, false, nil
 	}
 
+	self.envDir = envDir
+
 	// foundEnvs will overwrite envs and it's safe, because we append into
 	// foundEnvs the same number of items or less.
 	foundEnvs := envs[:0]
 	for _, envFile := range envs {
 		if exists, err := self.FileExistsInDir(envDir, envFile); err != nil {
-			return nil, err
+			return nil, true, err
 		} else if exists {
 			if envDir != "" {
 				envFile = filepath.Join(envDir, envFile)
@@ -273,22 +1243,59 @@ func (self *Loader) lookupEnvFiles() ([]string, error) {
 	}
 
 	// At least one .env file exists, because lookupEnvDir() returned found ==
-	// true. So here we never return empty slice.
-	return foundEnvs, nil
+	// true. So here we return empty slice only if the dir disappeared.
+	return foundEnvs, true, nil
+}
+
+// CandidateFiles returns names of .env files, which [Loader.Load] looks for, in
+// cascade order. Names are computed from configured name of environment,
+// preset and other options, without touching the filesystem. So external
+// tools can generate, lint or document expected set of .env files.
+func (self *Loader) CandidateFiles() []string {
+	return slices.Clone(self.envFiles())
 }
 
 // envFile returns list of .env files for searching, according to configured
 // name of environment. See [Loader.Load] for details.
 func (self *Loader) envFiles() []string {
+	envs := self.valueFiles()
+	if self.withoutLocal {
+		envs = slices.DeleteFunc(slices.Clone(envs), func(fname string) bool {
+			return strings.HasSuffix(fname, ".local")
+		})
+	}
+	return self.withSecretFiles(envs)
+}
+
+// valueFiles returns names of regular .env files, without secrets, in cascade
+// order.
+func (self *Loader) valueFiles() []string {
 	envName := self.envSuffix
+	if self.files != nil {
+		return self.files
+	} else if self.fileOrder != nil {
+		return self.fileOrder(envName)
+	} else if self.envFilesFn != nil {
+		return self.envFilesFn(envName)
+	}
+
+	base := self.baseName()
 	if envName == "" {
-		return []string{".env.local", ".env"}
+		return []string{base + ".local", base}
 	}
 
 	return []string{
-		".env." + envName + ".local", ".env.local",
-		".env." + envName, ".env",
+		base + "." + envName + ".local", base + ".local",
+		base + "." + envName, base,
+	}
+}
+
+// baseName returns base name of .env files, see [Loader.WithFileName].
+func (self *Loader) baseName() string {
+	if self.fileName == "" {
+		return ".env"
 	}
+	return self.fileName
 }
 
 // lookupEnvDir is searching for a dir, which contains any of files with names
@@ -302,33 +1309,161 @@ func (self *Loader) envFiles() []string {
 //
 // It starts searching at current dir, next tries parent dir, parent of parent
 // dir and so on, until it reaches configured root.
-func (self *Loader) lookupEnvDir(envFiles []string) (bool, string, error) {
-	curDir := ""
-	depth := 0
-
-	for {
-		for _, envFile := range envFiles {
-			if exists, err := self.FileExistsInDir(curDir, envFile); err != nil {
-				return false, "", err
-			} else if exists {
-				return exists, curDir, nil
+func (self *Loader) lookupEnvDir(envFiles []string) (bool, string, []string,
+	error,
+) {
+	for curDir, err := range self.dirs() {
+		if err != nil {
+			return false, "", envFiles, err
+		}
+		self.touchPath(cmp.Or(curDir, "."))
+		if err := self.checkContext(); err != nil {
+			return false, "", envFiles, err
+		}
+		self.traceDir(curDir)
+
+		envs := envFiles
+		files, err := self.envrcFiles(curDir)
+		if err == nil && len(files) != 0 {
+			envs = files
+		}
+
+		found := false
+		if err == nil {
+			found, err = self.hasEnvFiles(curDir, envs)
+		}
+
+		if err != nil {
+			if self.stopByPermission(curDir, err) {
+				break
 			}
+			return false, "", envFiles, err
+		} else if found {
+			self.stop = Stop{Reason: StopFound, Dir: curDir}
+			return true, curDir, envs, nil
 		}
+	}
+	return false, "", envFiles, nil
+}
 
-		if depth = self.checkLookupDepth(depth); depth < 0 {
-			break
+// hasEnvFiles returns true if dir contains any of envFiles and doesn't contain
+// ignore marker, configured by [Loader.WithIgnoreFile].
+func (self *Loader) hasEnvFiles(dir string, envFiles []string) (bool, error) {
+	for _, envFile := range envFiles {
+		if exists, err := self.FileExistsInDir(dir, envFile); err != nil {
+			return false, err
+		} else if exists {
+			if self.ignoreFile == "" {
+				return true, nil
+			}
+			ignore, err := self.FileExistsInDir(dir, self.ignoreFile)
+			if err != nil {
+				return false, err
+			} else if ignore {
+				self.skipFile(filepath.Join(dir, envFile),
+					"dir ignored by "+self.ignoreFile)
+			}
+			return !ignore, nil
 		}
+	}
+	return false, nil
+}
 
-		if newDir, err := self.nextParentDir(curDir); err != nil {
-			return false, "", fmt.Errorf("next parent dir of %v: %w", curDir, err)
-		} else if newDir == "" {
-			break
-		} else {
-			curDir = newDir
+// Dirs returns an iterator over dirs, [Loader.Load] visits searching for .env
+// files. It yields absolute path of every dir in order, starting from current
+// dir, until it reaches any of configured stop conditions, see [Loader.Load].
+// After yielding an error it stops. For instance:
+//
+//	for dir, err := range dotenv.New().Dirs() {
+//		if err != nil {
+//			return err
+//		}
+//		fmt.Println(dir)
+//	}
+func (self *Loader) Dirs() iter.Seq2[string, error] {
+	return func(yield func(string, error) bool) {
+		for dir, err := range self.dirs() {
+			if err == nil && dir == "" {
+				if dir, err = os.Getwd(); err != nil {
+					err = fmt.Errorf("can't get current dir: %w", err)
+				}
+			}
+			if !yield(dir, err) || err != nil {
+				return
+			}
+		}
+	}
+}
+
+// dirs returns an iterator over visited dirs, like [Loader.Dirs] does, but
+// current dir is yielded as empty string.
+func (self *Loader) dirs() iter.Seq2[string, error] {
+	return func(yield func(string, error) bool) {
+		curDir := self.startDir()
+		self.walkRoot = self.walkRootDir(curDir)
+		depth := 0
+
+		for n := 1; ; n++ {
+			self.walkDepth = n
+			self.stats.DirsVisited++
+			if !yield(curDir, nil) {
+				return
+			}
+
+			if depth = self.checkLookupDepth(depth); depth < 0 {
+				self.stop = Stop{Reason: StopDepth, Dir: curDir}
+				return
+			}
+
+			if newDir, err := self.nextParentDir(curDir); err != nil {
+				if !self.stopByPermission(curDir, err) {
+					yield("", fmt.Errorf("next parent dir of %v: %w", curDir, err))
+				}
+				return
+			} else if newDir == "" {
+				return
+			} else if newDir == curDir {
+				yield("", fmt.Errorf("loop at %v: %w", curDir, ErrDepthExceeded))
+				return
+			} else if self.maxDirs > 0 && n >= self.maxDirs {
+				yield("", fmt.Errorf("visited %v dirs, stopped at %v: %w", n, curDir,
+					ErrDepthExceeded))
+				return
+			} else {
+				curDir = newDir
+			}
 		}
 	}
+}
 
-	return false, "", nil
+// startDir returns dir, where lookup starts: empty string for current dir,
+// start dir of [Loader.ReplayLoad] or [Loader.For], or fallback start dir, if
+// current dir is unknown.
+func (self *Loader) startDir() string {
+	if self.startAt != "" {
+		return self.startAt
+	} else if self.fsys != nil {
+		return string(filepath.Separator)
+	} else if self.fallbackStartDir == "" {
+		return ""
+	} else if _, err := os.Getwd(); err != nil {
+		self.warnings = append(self.warnings, fmt.Errorf(
+			"start at %v: can't get current dir: %w", self.fallbackStartDir, err))
+		return self.fallbackStartDir
+	}
+	return ""
+}
+
+// stopByPermission returns true if err is a permission error and it's
+// configured by [Loader.WithIgnorePermissionErrors] to stop at dir in this
+// case. It also keeps err as a warning.
+func (self *Loader) stopByPermission(dir string, err error) bool {
+	if !self.ignorePermission || !errors.Is(err, os.ErrPermission) {
+		return false
+	}
+	self.stop = Stop{Reason: StopPermission, Dir: dir}
+	self.warnings = append(self.warnings, err)
+	return true
 }
 
 // checkLookupDepth compares current dir level curDir with configured one and
@@ -357,22 +1492,28 @@ func (self *Loader) nextParentDir(curDir string) (string, error) {
 		} else {
 			curDir = dir
 		}
+		self.renameDirCache(".", curDir)
 	}
 
 	if stopHere, err := self.stopByRootCb(curDir); err != nil {
 		return "", err
 	} else if stopHere {
+		self.stop = Stop{Reason: StopCallback, Dir: curDir}
 		return "", nil
-	} else if curDir == self.rootDir {
+	} else if curDir == self.walkRoot {
+		self.stop = Stop{Reason: StopRootDir, Dir: curDir}
 		return "", nil
 	}
 
-	for _, fname := range self.rootFiles {
-		if exists, err := self.FileExistsInDir(curDir, fname); err != nil {
-			return "", fmt.Errorf("check existence of file %v in dir %v: %w", fname,
-				curDir, err)
-		} else if exists {
-			return "", nil
+	for _, fnames := range [...][]string{self.rootFiles, self.preset.rootFiles()} {
+		for _, fname := range fnames {
+			if found, err := self.rootFileInDir(curDir, fname); err != nil {
+				return "", fmt.Errorf("check existence of file %v in dir %v: %w",
+					fname, curDir, err)
+			} else if found != "" {
+				self.stop = Stop{Reason: StopRootFile, Dir: curDir, RootFile: found}
+				return "", nil
+			}
 		}
 	}
 
@@ -384,7 +1525,16 @@ func (self *Loader) nextParentDir(curDir string) (string, error) {
 // and false means continue to parent dir.
 func (self *Loader) stopByRootCb(path string) (bool, error) {
 	if self.rootCb != nil {
-		if stopHere, err := self.rootCb(path); err != nil {
+		info := WalkInfo{
+			Path:         path,
+			Depth:        self.walkDepth,
+			DirsVisited:  self.stats.DirsVisited,
+			StatCalls:    self.stats.StatCalls,
+			ReadDirCalls: self.stats.ReadDirCalls,
+			Skipped:      slices.Clone(self.skipped),
+			loader:       self,
+		}
+		if stopHere, err := self.rootCb(info); err != nil {
 			return false, fmt.Errorf("check dir %v using root callback: %w", path, err)
 		} else {
 			return stopHere, nil
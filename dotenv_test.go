@@ -1,9 +1,13 @@
 package dotenv
 
 import (
+	"fmt"
 	"os"
 	"path/filepath"
+	"strconv"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
@@ -14,7 +18,7 @@ import (
 
 var allEnvVars = []string{"TEST_VAR1", "TEST_VAR2"}
 
-func valueNoError[V any](t *testing.T) func(val V, err error) V {
+func valueNoError[V any](t testing.TB) func(val V, err error) V {
 	return func(val V, err error) V {
 		require.NoError(t, err)
 		return val
@@ -89,6 +93,13 @@ func TestWithRootFiles(t *testing.T) {
 	assert.Equal(t, []string{".git", "go.mod"}, env.rootFiles)
 }
 
+func TestWithIgnoreFile(t *testing.T) {
+	env := New()
+	assert.Equal(t, ".dotenvignore", env.ignoreFile)
+	assert.Same(t, env, env.WithIgnoreFile(".skip"))
+	assert.Equal(t, ".skip", env.ignoreFile)
+}
+
 func TestWithRootCallback(t *testing.T) {
 	env := New()
 	assert.Nil(t, env.rootCb)
@@ -209,6 +220,20 @@ func TestLoader_envFiles(t *testing.T) {
 		env.envFiles())
 }
 
+func TestLoader_CandidateFiles(t *testing.T) {
+	env := New().WithEnvSuffix("test")
+	files := env.CandidateFiles()
+	assert.Equal(t, env.envFiles(), files)
+
+	env.WithPreset(PresetRails)
+	assert.Equal(t, []string{".env.test.local", ".env.test", ".env"},
+		env.CandidateFiles())
+
+	env.WithPreset(PresetDefault).WithSecretsEnabled()
+	assert.Equal(t, append([]string{".env.test.secrets", ".env.secrets"},
+		files...), env.CandidateFiles())
+}
+
 func TestLoader_checkLookupDepth(t *testing.T) {
 	tests := []struct {
 		name        string
@@ -378,6 +403,60 @@ func TestLoader_Load(t *testing.T) {
 				})
 			},
 		},
+		{
+			name:       "WithEncodedValues base64",
+			dir:        "testdata/c",
+			envVarName: allEnvVars[0],
+			expect:     "hello",
+			before: func(t *testing.T, env *Loader) {
+				env.WithEncodedValues()
+			},
+		},
+		{
+			name:       "WithEncodedValues hex",
+			dir:        "testdata/c",
+			envVarName: allEnvVars[1],
+			expect:     "world",
+			before: func(t *testing.T, env *Loader) {
+				env.WithEncodedValues()
+			},
+		},
+		{
+			name:       "without WithEncodedValues",
+			dir:        "testdata/c",
+			envVarName: allEnvVars[0],
+			expect:     "base64:aGVsbG8=",
+		},
+		{
+			name:       "WithEncodedValues error",
+			dir:        "testdata/c",
+			envVarName: allEnvVars[0],
+			expectErr:  true,
+			before: func(t *testing.T, env *Loader) {
+				env.WithEncodedValues().WithEnvSuffix("error")
+			},
+		},
+		{
+			name:       "without WithContinueOnError",
+			dir:        "testdata/d",
+			envVarName: allEnvVars[0],
+			expectErr:  true,
+		},
+		{
+			name:       "skip dir with .dotenvignore",
+			dir:        "testdata/e/f",
+			envVarName: allEnvVars[0],
+			expect:     "testdata",
+		},
+		{
+			name:       "WithIgnoreFile empty",
+			dir:        "testdata/e/f",
+			envVarName: allEnvVars[0],
+			expect:     "testdata-e",
+			before: func(t *testing.T, env *Loader) {
+				env.WithIgnoreFile("")
+			},
+		},
 		{
 			name:       "WithRootFiles stop at go.mod",
 			dir:        "testdata/b",
@@ -405,7 +484,7 @@ func TestLoader_Load(t *testing.T) {
 	}
 }
 
-func changeDir(t *testing.T, path string) {
+func changeDir(t testing.TB, path string) {
 	curDir := valueNoError[string](t)(os.Getwd())
 	require.NoError(t, os.Chdir(path))
 	t.Cleanup(func() {
@@ -437,6 +516,32 @@ func TestLoader_Load_errorGetwd(t *testing.T) {
 	require.Error(t, env.Load())
 }
 
+func TestLoader_WithFallbackStartDir(t *testing.T) {
+	restoreEnvVars(t)
+	tmpDir := valueNoError[string](t)(os.MkdirTemp("", "expx-dotenv-"))
+	t.Cleanup(func() {
+		require.NoError(t, os.RemoveAll(tmpDir))
+	})
+
+	env := New()
+	assert.Same(t, env, env.WithFallbackStartDir("testdata"))
+	testdata := valueNoError[string](t)(filepath.Abs("testdata"))
+	assert.Equal(t, testdata, env.fallbackStartDir)
+
+	curDir := valueNoError[string](t)(os.Getwd())
+	require.NoError(t, os.Chdir(tmpDir))
+	t.Cleanup(func() {
+		require.NoError(t, os.Chdir(curDir))
+	})
+	require.NoError(t, os.RemoveAll(tmpDir))
+
+	require.NoError(t, env.Load())
+	assert.Equal(t, "testdata", os.Getenv(allEnvVars[0]))
+	assert.Equal(t, testdata, env.Result().Stop.Dir)
+	require.Len(t, env.Result().Warnings, 1)
+	assert.ErrorContains(t, env.Result().Warnings[0], "can't get current dir")
+}
+
 func TestLoader_nextParentDir_error(t *testing.T) {
 	filer := mocks.NewMockFiler(t)
 	filer.EXPECT().Stat(mock.Anything).Return(nil, os.ErrInvalid)
@@ -452,7 +557,7 @@ func TestLoader_lookupEnvDir_error(t *testing.T) {
 	filer.EXPECT().Stat(mock.Anything).Return(nil, os.ErrInvalid)
 	l := New(WithFiler(filer))
 
-	found, envDir, err := l.lookupEnvDir(l.envFiles())
+	found, envDir, _, err := l.lookupEnvDir(l.envFiles())
 	require.ErrorIs(t, err, os.ErrInvalid)
 	assert.False(t, found)
 	assert.Equal(t, "", envDir)
@@ -477,6 +582,55 @@ func TestLoader_lookupEnvFiles_error(t *testing.T) {
 	assert.Nil(t, envs)
 }
 
+func TestLoader_lookupEnvFiles_vanished(t *testing.T) {
+	tests := []struct {
+		name   string
+		exists []bool
+		expect []string
+		stop   StopReason
+	}{
+		{
+			name:   "retry",
+			exists: []bool{true, false, true, true},
+			expect: []string{".env"},
+			stop:   StopFound,
+		},
+		{
+			name:   "vanished",
+			exists: []bool{true, false, true, false},
+			stop:   StopVanished,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var calls int
+			filer := mocks.NewMockFiler(t)
+			filer.EXPECT().Stat(mock.Anything).RunAndReturn(
+				func(name string) (os.FileInfo, error) {
+					if name == ".env" {
+						calls++
+						if tt.exists[calls-1] {
+							return nil, nil
+						}
+					}
+					return nil, os.ErrNotExist
+				})
+
+			l := New(WithFiler(filer))
+			envs, err := l.lookupEnvFiles()
+			require.NoError(t, err)
+			assert.Equal(t, tt.expect, envs)
+			assert.Equal(t, tt.stop, l.stop.Reason)
+			assert.Equal(t, len(tt.exists), calls)
+			if tt.stop == StopVanished {
+				require.Len(t, l.warnings, 1)
+				assert.ErrorContains(t, l.warnings[0], "disappeared")
+			}
+		})
+	}
+}
+
 func TestLoader_Load_withCallbacks(t *testing.T) {
 	var callCnt int
 
@@ -570,3 +724,511 @@ func TestLoad(t *testing.T) {
 	require.NoError(t, Load())
 	assert.Equal(t, "testdata", os.Getenv(allEnvVars[0]))
 }
+
+func TestLoader_WithDirChangeHook(t *testing.T) {
+	restoreEnvVars(t)
+	curDir := valueNoError[string](t)(os.Getwd())
+	changeDir(t, "testdata")
+
+	var changes [][2]string
+	env := New()
+	assert.Same(t, env, env.WithDirChangeHook(func(prevDir, curDir string) {
+		changes = append(changes, [2]string{prevDir, curDir})
+	}))
+
+	require.NoError(t, env.Load())
+	require.NoError(t, env.Load())
+	assert.Empty(t, changes)
+
+	changeDir(t, "a")
+	require.NoError(t, env.Load())
+	assert.Equal(t, [][2]string{{
+		filepath.Join(curDir, "testdata"),
+		filepath.Join(curDir, "testdata", "a"),
+	}}, changes)
+}
+
+func TestLoader_WithMaxLoadDuration(t *testing.T) {
+	changeDir(t, "testdata/a")
+	restoreEnvVars(t)
+
+	var warnings []error
+	env := New()
+	assert.Same(t, env, env.WithWarningHook(func(warn error) {
+		warnings = append(warnings, warn)
+	}))
+	assert.Same(t, env, env.WithMaxLoadDuration(time.Hour))
+	require.NoError(t, env.Load())
+	assert.Empty(t, warnings)
+	assert.Empty(t, env.Result().Warnings)
+
+	env.WithMaxLoadDuration(time.Nanosecond)
+	require.NoError(t, env.Load())
+	require.Len(t, warnings, 1)
+	require.ErrorIs(t, warnings[0], ErrSlowLoad)
+	assert.Equal(t, warnings, env.Result().Warnings)
+}
+
+func TestLoader_Result(t *testing.T) {
+	changeDir(t, "testdata/a")
+	restoreEnvVars(t)
+
+	var hookStats []Stats
+	env := New().WithStatsHook(func(stats Stats) {
+		hookStats = append(hookStats, stats)
+	})
+	assert.Nil(t, env.Result())
+	require.NoError(t, env.Load())
+
+	result := env.Result()
+	require.NotNil(t, result)
+	stats := result.Stats
+	assert.Equal(t, 2, stats.DirsVisited)
+	assert.Zero(t, stats.StatCalls)
+	assert.Equal(t, 2, stats.ReadDirCalls)
+	require.Len(t, stats.Files, 1)
+	assert.Equal(t, ".env", filepath.Base(stats.Files[0].Name))
+	assert.Positive(t, stats.WallTime)
+	assert.Equal(t, []Stats{stats}, hookStats)
+
+	require.Error(t, env.WithEnvSuffix("error").Load())
+	require.Len(t, hookStats, 2)
+	assert.Equal(t, env.Result().Stats, hookStats[1])
+}
+
+func TestLoader_WithContinueOnError(t *testing.T) {
+	changeDir(t, "testdata/d")
+	restoreEnvVars(t)
+
+	var called bool
+	env := New().WithEnvSuffix("error").WithContinueOnError()
+	err := env.Load(func() error {
+		called = true
+		return nil
+	})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), ".env.error")
+	assert.Contains(t, err.Error(), ".env.local")
+	assert.False(t, called)
+	assert.Equal(t, "testdata-d", os.Getenv(allEnvVars[0]))
+	assert.Len(t, env.Result().Stats.Files, 3)
+}
+
+func TestLoader_WithWalkCallback(t *testing.T) {
+	curDir := valueNoError[string](t)(os.Getwd())
+	changeDir(t, "testdata/e/f")
+	restoreEnvVars(t)
+
+	var infos []WalkInfo
+	env := New()
+	assert.Same(t, env, env.WithWalkCallback(func(info WalkInfo) (bool, error) {
+		infos = append(infos, info)
+		return len(info.Skipped) != 0, nil
+	}))
+	require.NoError(t, env.Load())
+	assert.Empty(t, os.Getenv(allEnvVars[0]))
+
+	require.Len(t, infos, 2)
+	assert.Equal(t, filepath.Join(curDir, "testdata", "e", "f"), infos[0].Path)
+	assert.Equal(t, 1, infos[0].Depth)
+	assert.Equal(t, 1, infos[0].DirsVisited)
+	assert.Positive(t, infos[0].ReadDirCalls)
+	assert.Empty(t, infos[0].Skipped)
+
+	assert.Equal(t, filepath.Join(curDir, "testdata", "e"), infos[1].Path)
+	assert.Equal(t, 2, infos[1].Depth)
+	assert.Equal(t, 2, infos[1].DirsVisited)
+	assert.Len(t, infos[1].Skipped, 1)
+	assert.Equal(t, StopCallback, env.Result().Stop.Reason)
+
+	assert.Same(t, env, env.WithRootCallback(nil))
+	assert.Nil(t, env.rootCb)
+}
+
+func TestLoader_Result_skipped(t *testing.T) {
+	curDir := valueNoError[string](t)(os.Getwd())
+	changeDir(t, "testdata/e/f")
+	restoreEnvVars(t)
+
+	env := New()
+	require.NoError(t, env.Load())
+	assert.Equal(t, []SkippedFile{{
+		Name:   filepath.Join(curDir, "testdata", "e", ".env"),
+		Reason: "dir ignored by .dotenvignore",
+	}}, env.Result().Skipped)
+
+	changeDir(t, "../../d")
+	require.Error(t, env.WithEnvSuffix("error").WithContinueOnError().Load())
+	skipped := env.Result().Skipped
+	require.Len(t, skipped, 2)
+	assert.Equal(t, ".env.local", skipped[0].Name)
+	assert.Equal(t, ".env.error", skipped[1].Name)
+	assert.NotEmpty(t, skipped[1].Reason)
+}
+
+func TestLoader_Dirs(t *testing.T) {
+	curDir := valueNoError[string](t)(os.Getwd())
+	changeDir(t, "testdata/a")
+
+	var dirs []string
+	for dir, err := range New().Dirs() {
+		require.NoError(t, err)
+		dirs = append(dirs, dir)
+	}
+	assert.Equal(t, []string{
+		filepath.Join(curDir, "testdata", "a"),
+		filepath.Join(curDir, "testdata"),
+		curDir,
+	}, dirs)
+
+	dirs = dirs[:0]
+	for dir, err := range New().Dirs() {
+		require.NoError(t, err)
+		dirs = append(dirs, dir)
+		break
+	}
+	assert.Equal(t, []string{filepath.Join(curDir, "testdata", "a")}, dirs)
+
+	dirs = dirs[:0]
+	for dir, err := range New().WithDepth(2).Dirs() {
+		require.NoError(t, err)
+		dirs = append(dirs, dir)
+	}
+	assert.Len(t, dirs, 2)
+}
+
+func TestLoader_Dirs_error(t *testing.T) {
+	filer := mocks.NewMockFiler(t)
+	filer.EXPECT().Stat(mock.Anything).Return(nil, os.ErrInvalid)
+
+	var dirs []string
+	var errs []error
+	for dir, err := range New(WithFiler(filer)).Dirs() {
+		dirs = append(dirs, dir)
+		errs = append(errs, err)
+	}
+	require.Len(t, errs, 2)
+	require.NoError(t, errs[0])
+	require.ErrorIs(t, errs[1], os.ErrInvalid)
+	assert.Equal(t, "", dirs[1])
+}
+
+func BenchmarkLookup(b *testing.B) {
+	for _, depth := range []int{4, 32} {
+		b.Run(fmt.Sprintf("depth=%v", depth), func(b *testing.B) {
+			rootDir := b.TempDir()
+			require.NoError(b, os.WriteFile(filepath.Join(rootDir, ".env"), nil,
+				0o600))
+			changeDir(b, makeDeepDir(b, rootDir, depth))
+
+			env := New().WithRootDir(rootDir).WithEnvSuffix("test")
+			b.ReportAllocs()
+			b.ResetTimer()
+			for range b.N {
+				envs, err := env.lookupEnvFiles()
+				if err != nil {
+					b.Fatal(err)
+				} else if len(envs) != 1 {
+					b.Fatalf("unexpected %v", envs)
+				}
+			}
+		})
+	}
+}
+
+func BenchmarkLoad(b *testing.B) {
+	for _, numKeys := range []int{10, 10_000} {
+		b.Run(fmt.Sprintf("keys=%v", numKeys), func(b *testing.B) {
+			rootDir := b.TempDir()
+			var sb strings.Builder
+			for i := range numKeys {
+				fmt.Fprintf(&sb, "BENCH_KEY_%v=value %v\n", i, i)
+			}
+			require.NoError(b, os.WriteFile(filepath.Join(rootDir, ".env"),
+				[]byte(sb.String()), 0o600))
+			changeDir(b, makeDeepDir(b, rootDir, 8))
+
+			env := New().WithRootDir(rootDir).WithoutSetenv()
+			b.ReportAllocs()
+			b.ResetTimer()
+			for range b.N {
+				env.vars = nil
+				if err := env.Load(); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+func makeDeepDir(b *testing.B, rootDir string, depth int) string {
+	dir := rootDir
+	for i := range depth {
+		dir = filepath.Join(dir, strconv.Itoa(i+1))
+	}
+	require.NoError(b, os.MkdirAll(dir, 0o755))
+	return dir
+}
+
+func TestLoader_Result_stop(t *testing.T) {
+	curDir := valueNoError[string](t)(os.Getwd())
+	testdataDir := filepath.Join(curDir, "testdata")
+
+	tests := []struct {
+		name   string
+		dir    string
+		before func(env *Loader)
+		expect Stop
+	}{
+		{
+			name:   "found",
+			dir:    "testdata/a",
+			expect: Stop{Reason: StopFound, Dir: testdataDir},
+		},
+		{
+			name:   "found in current dir",
+			dir:    "testdata",
+			expect: Stop{Reason: StopFound},
+		},
+		{
+			name:   "depth",
+			dir:    "testdata/a",
+			before: func(env *Loader) { env.WithDepth(1) },
+			expect: Stop{Reason: StopDepth},
+		},
+		{
+			name: "root dir",
+			dir:  "testdata/a",
+			before: func(env *Loader) {
+				env.WithRootDir(".")
+			},
+			expect: Stop{Reason: StopRootDir, Dir: filepath.Join(testdataDir, "a")},
+		},
+		{
+			name: "root file",
+			dir:  "testdata/b",
+			expect: Stop{
+				Reason:   StopRootFile,
+				Dir:      filepath.Join(testdataDir, "b"),
+				RootFile: "go.mod",
+			},
+		},
+		{
+			name: "callback",
+			dir:  "testdata/a",
+			before: func(env *Loader) {
+				env.WithRootCallback(func(string) (bool, error) { return true, nil })
+			},
+			expect: Stop{Reason: StopCallback, Dir: filepath.Join(testdataDir, "a")},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			changeDir(t, tt.dir)
+			restoreEnvVars(t)
+			env := New()
+			if tt.before != nil {
+				tt.before(env)
+			}
+			require.NoError(t, env.Load())
+			assert.Equal(t, tt.expect, env.Result().Stop)
+		})
+	}
+}
+
+func TestStopReason_String(t *testing.T) {
+	assert.Equal(t, "root file", StopRootFile.String())
+	assert.Equal(t, "vanished", StopVanished.String())
+	assert.Equal(t, "StopReason(100)", StopReason(100).String())
+}
+
+func TestLoader_WithMaxDirs(t *testing.T) {
+	changeDir(t, "testdata/a")
+	restoreEnvVars(t)
+
+	env := New()
+	assert.Equal(t, DefaultMaxDirs, env.maxDirs)
+	assert.Same(t, env, env.WithMaxDirs(1))
+	require.ErrorIs(t, env.Load(), ErrDepthExceeded)
+	assert.Equal(t, 1, env.Result().Stats.DirsVisited)
+
+	require.NoError(t, env.WithMaxDirs(2).Load())
+	require.NoError(t, env.WithMaxDirs(0).Load())
+	assert.Equal(t, "testdata", os.Getenv(allEnvVars[0]))
+}
+
+func TestLoader_Load_loop(t *testing.T) {
+	filer := mocks.NewMockFiler(t)
+	filer.EXPECT().Stat(mock.Anything).Return(nil, os.ErrNotExist)
+
+	env := New(WithFiler(filer))
+	env.rootDir = "/not/reachable"
+
+	var dirs []string
+	var err error
+	for dir, dirErr := range env.dirs() {
+		if dirErr != nil {
+			err = dirErr
+			break
+		}
+		dirs = append(dirs, dir)
+	}
+	require.ErrorIs(t, err, ErrDepthExceeded)
+	assert.Equal(t, string(filepath.Separator), dirs[len(dirs)-1])
+}
+
+func TestLoader_WithIgnorePermissionErrors(t *testing.T) {
+	filer := mocks.NewMockFiler(t)
+	filer.EXPECT().Stat(mock.Anything).RunAndReturn(
+		func(name string) (os.FileInfo, error) {
+			if filepath.IsAbs(name) {
+				return nil, os.ErrPermission
+			}
+			return os.Stat(name)
+		})
+
+	changeDir(t, "testdata/a")
+	restoreEnvVars(t)
+
+	env := New(WithFiler(filer))
+	require.ErrorIs(t, env.Load(), os.ErrPermission)
+
+	assert.Same(t, env, env.WithIgnorePermissionErrors())
+	require.NoError(t, env.Load())
+	assert.Equal(t, "", os.Getenv(allEnvVars[0]))
+
+	result := env.Result()
+	assert.Equal(t, StopPermission, result.Stop.Reason)
+	require.Len(t, result.Warnings, 1)
+	require.ErrorIs(t, result.Warnings[0], os.ErrPermission)
+}
+
+func TestLoader_WithoutLocalFiles(t *testing.T) {
+	env := New().WithEnvSuffix("test")
+	assert.Same(t, env, env.WithoutLocalFiles())
+	assert.Equal(t, []string{".env.test", ".env"}, env.CandidateFiles())
+	assert.Equal(t, []string{".env.test", ".env"},
+		New(WithEnvSuffix("test"), WithoutLocalFiles()).CandidateFiles())
+
+	changeDir(t, "testdata/g")
+	restoreEnvVars(t)
+	require.NoError(t, env.Load())
+	assert.Equal(t, "last", os.Getenv(allEnvVars[0]))
+}
+
+func TestLoader_LoadResult(t *testing.T) {
+	curDir := valueNoError[string](t)(os.Getwd())
+	changeDir(t, "testdata/g")
+	restoreEnvVars(t)
+	t.Setenv(allEnvVars[1], "from env")
+
+	env := New()
+	res, err := env.LoadResult()
+	require.NoError(t, err)
+	require.NotNil(t, res)
+	assert.Same(t, env.Result(), res)
+	assert.Empty(t, res.Dir)
+	assert.Equal(t, []string{".env.local", ".env"}, res.Files)
+	assert.Equal(t, []string{allEnvVars[0]}, res.SetKeys)
+	assert.Equal(t, []string{allEnvVars[1]}, res.ExistingKeys)
+	assert.Positive(t, res.Stats.WallTime)
+
+	restoreEnvVars(t)
+	changeDir(t, "../e/f")
+	res, err = env.LoadResult()
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(curDir, "testdata"), res.Dir)
+	assert.Equal(t, []string{filepath.Join(curDir, "testdata", ".env")},
+		res.Files)
+
+	res, err = env.WithMiddleware(func(next LoadFunc) LoadFunc {
+		return func() error { return os.ErrInvalid }
+	}).LoadResult()
+	require.ErrorIs(t, err, os.ErrInvalid)
+	assert.Nil(t, res)
+}
+
+func TestLoader_WithFileName(t *testing.T) {
+	dir := t.TempDir()
+	for fname, content := range map[string]string{
+		".env":             "TEST_VAR1=env\nTEST_VAR2=env\n",
+		"app.env":          "TEST_VAR1=app\nTEST_VAR2=app\n",
+		"app.env.test":     "TEST_VAR2=app test\n",
+		"app.env.mylib":    "TEST_VAR1=app mylib\n",
+		"app.env.local":    "TEST_VAR1=app local\n",
+		"app.env.secrets":  "TEST_VAR3=secret\n",
+		"go.mod":           "module example\n",
+		"app.env.unused.x": "TEST_VAR1=unused\n",
+	} {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, fname),
+			[]byte(content), 0o600))
+	}
+	changeDir(t, dir)
+
+	env := New(WithFileName("app.env")).WithEnvSuffix("test").WithoutSetenv()
+	require.NoError(t, env.Load())
+	assert.Equal(t, []string{"app.env.local", "app.env.test", "app.env"},
+		env.Result().Files)
+	assert.Equal(t, "app local", env.Getenv(allEnvVars[0]))
+	assert.Equal(t, "app test", env.Getenv(allEnvVars[1]))
+
+	assert.Equal(t, []string{"app.env.test.secrets", "app.env.secrets"},
+		env.secretFiles())
+
+	ns := env.Namespace("mylib")
+	require.NoError(t, ns.Load())
+	assert.Equal(t, "app mylib", ns.Getenv(allEnvVars[0]))
+
+	require.ErrorIs(t, env.WithFileName("a/app.env").Load(), ErrInvalidConfig)
+	env = New(WithFileName("app.env")).WithFileName("").WithoutSetenv()
+	require.NoError(t, env.Load())
+	assert.Equal(t, "env", env.Getenv(allEnvVars[0]))
+}
+
+func TestLoader_WithFiles(t *testing.T) {
+	changeDir(t, "testdata/g")
+	restoreEnvVars(t)
+
+	env := New(WithFiles(".env.test", ".env")).WithEnvSuffix("production").
+		WithoutSetenv()
+	require.NoError(t, env.Load())
+	assert.Equal(t, []string{".env.test", ".env"}, env.Result().Files)
+	assert.Equal(t, "last", env.Getenv(allEnvVars[0]))
+	assert.Equal(t, "test", env.Getenv(allEnvVars[1]))
+
+	require.ErrorIs(t, env.WithFiles(".env", "").Load(), ErrInvalidConfig)
+	require.ErrorIs(t, env.WithFiles("a/.env").Load(), ErrInvalidConfig)
+
+	env = New().WithFiles(".env.local").WithFiles().WithoutSetenv()
+	require.NoError(t, env.Load())
+	assert.Equal(t, []string{".env.local", ".env"}, env.Result().Files)
+}
+
+func TestLoader_WithFileOrder(t *testing.T) {
+	changeDir(t, "testdata/g")
+	restoreEnvVars(t)
+
+	var gotEnvName string
+	env := New(WithFileOrder(func(envName string) []string {
+		gotEnvName = envName
+		return []string{".env." + envName, ".env.local"}
+	})).WithEnvSuffix("test").WithPreset(PresetVite).WithoutSetenv()
+	require.NoError(t, env.Load())
+	assert.Equal(t, "test", gotEnvName)
+	assert.Equal(t, []string{".env.test", ".env.local"}, env.Result().Files)
+	assert.Equal(t, "local", env.Getenv(allEnvVars[0]))
+	assert.Equal(t, "test", env.Getenv(allEnvVars[1]))
+
+	env.WithFiles(".env")
+	require.NoError(t, env.Load())
+	assert.Equal(t, []string{".env"}, env.Result().Files)
+
+	env.WithFiles().WithEnvSuffix("../a")
+	require.ErrorIs(t, env.Load(), ErrInvalidConfig)
+
+	env = New().WithFileOrder(func(string) []string { return nil }).
+		WithFileOrder(nil).WithoutSetenv()
+	require.NoError(t, env.Load())
+	assert.Equal(t, []string{".env.local", ".env"}, env.Result().Files)
+}
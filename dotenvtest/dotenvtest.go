@@ -0,0 +1,92 @@
+// Package dotenvtest loads .env files of tests in TestMain and provides
+// [Harness] for deterministic tests with fake files, env vars and clock.
+package dotenvtest
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	dotenv "github.com/dsh2dsh/expx-dotenv"
+)
+
+// EnvName is a name of environment, which .env files AutoLoad loads.
+const EnvName = "test"
+
+// AutoLoad loads .env files of "test" environment and runs tests of m. It's
+// designed for TestMain:
+//
+//	func TestMain(m *testing.M) {
+//		os.Exit(dotenvtest.AutoLoad(m))
+//	}
+//
+// It looks for ".env.test" and ".env" files starting at dir of the package,
+// which calls it, not at current dir, and up to the nearest dir with go.mod,
+// like [dotenv.Loader.Load] does by default. .local files are skipped, see
+// [dotenv.Loader.WithoutLocalFiles], so results of tests don't depend on
+// overrides of developer machines. opts configure [dotenv.Loader] further.
+//
+// Env vars of the process environment keep their values. The environment is
+// restored after tests, so every env var, changed by loaded files or by tests,
+// gets its previous value back.
+//
+// It returns exit code of m.Run or 1, if loading failed.
+func AutoLoad(m *testing.M, opts ...dotenv.Option) int {
+	return autoLoad(callerDir(), m.Run, opts...)
+}
+
+// callerDir returns dir of source file, which called AutoLoad, or empty string
+// for current dir, if it's unknown, like with -trimpath.
+func callerDir() string {
+	if _, file, _, ok := runtime.Caller(2); ok && filepath.IsAbs(file) {
+		return filepath.Dir(file)
+	}
+	return ""
+}
+
+func autoLoad(dir string, run func() int, opts ...dotenv.Option) int {
+	environ := os.Environ()
+	defer restoreEnv(environ)
+
+	if err := load(dir, opts); err != nil {
+		fmt.Fprintln(os.Stderr, "dotenvtest:", err)
+		return 1
+	}
+	return run()
+}
+
+// load loads .env files for dir into the process environment.
+func load(dir string, opts []dotenv.Option) error {
+	env := dotenv.New(dotenv.WithEnvSuffix(EnvName), dotenv.WithoutLocalFiles())
+	env.With(opts...)
+	if dir == "" {
+		return env.Load() //nolint:wrapcheck // it's descriptive enough
+	}
+
+	scoped, err := env.For(dir)
+	if err != nil {
+		return err //nolint:wrapcheck // it's descriptive enough
+	}
+
+	for k, v := range scoped.Map() {
+		if _, ok := os.LookupEnv(k); ok {
+			continue
+		} else if err := os.Setenv(k, v); err != nil {
+			return fmt.Errorf("set env var %v: %w", k, err)
+		}
+	}
+	return nil
+}
+
+// restoreEnv replaces the process environment by environ.
+func restoreEnv(environ []string) {
+	os.Clearenv()
+	for _, s := range environ {
+		if k, v, ok := strings.Cut(s, "="); ok {
+			_ = os.Setenv(k, v)
+		}
+	}
+}
@@ -0,0 +1,54 @@
+package dotenvtest
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	dotenv "github.com/dsh2dsh/expx-dotenv"
+)
+
+func TestAutoLoad(t *testing.T) {
+	t.Setenv("TEST_VAR1", "")
+	t.Setenv("TEST_VAR2", "process")
+	t.Setenv("TEST_VAR3", "")
+	os.Unsetenv("TEST_VAR1")
+	os.Unsetenv("TEST_VAR3")
+
+	dir, err := filepath.Abs("testdata/pkg")
+	require.NoError(t, err)
+
+	code := autoLoad(dir, func() int {
+		assert.Equal(t, "test", os.Getenv("TEST_VAR1"))
+		assert.Equal(t, "process", os.Getenv("TEST_VAR2"))
+		require.NoError(t, os.Setenv("TEST_VAR3", "changed by test"))
+		return 7
+	}, dotenv.WithDepth(2))
+	assert.Equal(t, 7, code)
+
+	_, ok := os.LookupEnv("TEST_VAR1")
+	assert.False(t, ok)
+	assert.Equal(t, "process", os.Getenv("TEST_VAR2"))
+	_, ok = os.LookupEnv("TEST_VAR3")
+	assert.False(t, ok)
+}
+
+func TestAutoLoad_error(t *testing.T) {
+	var called bool
+	code := autoLoad("testdata", func() int {
+		called = true
+		return 0
+	}, dotenv.WithEnvSuffix("a/b"))
+	assert.Equal(t, 1, code)
+	assert.False(t, called)
+}
+
+func TestCallerDir(t *testing.T) {
+	dir := func() string { return callerDir() }()
+	wd, err := os.Getwd()
+	require.NoError(t, err)
+	assert.Equal(t, wd, dir)
+}
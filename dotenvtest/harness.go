@@ -0,0 +1,244 @@
+package dotenvtest
+
+import (
+	"io/fs"
+	"maps"
+	"path"
+	"slices"
+	"strings"
+	"sync"
+	"testing/fstest"
+	"time"
+
+	dotenv "github.com/dsh2dsh/expx-dotenv"
+)
+
+// Harness is a fake environment for deterministic tests of code, which uses
+// [dotenv.Loader]: a fake filesystem, a fake process environment and a fake
+// clock, which sets modification time of written files and drives timers of
+// [dotenv.Loader.Watch]. Nothing touches real files or env vars:
+//
+//	h := dotenvtest.NewHarness()
+//	h.WriteFile("/app/.env", "DATABASE_URL=postgres://localhost/test\n")
+//	h.WriteFile("/app/go.mod", "module app\n")
+//	h.Setenv("DEBUG", "true")
+//
+//	scoped, err := h.Loader().For("/app")
+//
+// Watch of its [dotenv.Loader] polls the fake filesystem, so tests can change
+// files and advance the clock, without sleeps:
+//
+//	env := h.Loader().WithPollInterval(time.Second)
+//	go env.Watch(ctx, onChange)
+//	h.WaitTimers(1) // Watch loaded .env files and waits for polling
+//	h.WriteFile("/app/.env", "DEBUG=false\n")
+//	h.Advance(time.Second) // Watch notices the change
+//	h.WaitTimers(2)
+//	h.Advance(dotenv.WatchDelay) // Watch reloads and calls onChange
+//
+// Files, written by its methods, can be read by [dotenv.Loader] concurrently,
+// but FS itself isn't safe for concurrent use.
+type Harness struct {
+	// FS is a fake filesystem with absolute paths of [dotenv.Loader.WithFS]
+	// as its names without leading "/".
+	FS fstest.MapFS
+
+	// Env is a fake process environment.
+	Env *ProcessEnv
+
+	fsMu sync.RWMutex
+
+	mu     sync.Mutex
+	cond   *sync.Cond
+	now    time.Time
+	timers []fakeTimer
+}
+
+// fakeTimer is a pending timer of fake clock.
+type fakeTimer struct {
+	at time.Time
+	c  chan time.Time
+}
+
+// NewHarness creates and returns [Harness] with empty filesystem and
+// environment. Its clock starts at 2000-01-01 00:00:00 UTC.
+func NewHarness() *Harness {
+	h := &Harness{
+		FS:  make(fstest.MapFS),
+		Env: NewProcessEnv(nil),
+		now: time.Date(2000, time.January, 1, 0, 0, 0, 0, time.UTC),
+	}
+	h.cond = sync.NewCond(&h.mu)
+	return h
+}
+
+// Loader creates and returns [dotenv.Loader] configured by opts, which uses
+// filesystem, environment and clock of the harness.
+func (self *Harness) Loader(opts ...dotenv.Option) *dotenv.Loader {
+	opts = append([]dotenv.Option{
+		dotenv.WithProcessEnv(self.Env),
+		dotenv.WithClock(self),
+	}, opts...)
+	return dotenv.New(opts...).WithFS(harnessFS{self})
+}
+
+// WriteFile writes file name with content into fake filesystem. Its
+// modification time is current time of fake clock.
+func (self *Harness) WriteFile(name, content string) {
+	f := &fstest.MapFile{Data: []byte(content), Mode: 0o600, ModTime: self.Now()}
+	self.fsMu.Lock()
+	defer self.fsMu.Unlock()
+	self.FS[fsName(name)] = f
+}
+
+// RemoveFile removes file name from fake filesystem.
+func (self *Harness) RemoveFile(name string) {
+	self.fsMu.Lock()
+	defer self.fsMu.Unlock()
+	delete(self.FS, fsName(name))
+}
+
+// Setenv sets env var key of fake environment to value.
+func (self *Harness) Setenv(key, value string) { _ = self.Env.Setenv(key, value) }
+
+// Now returns current time of fake clock.
+func (self *Harness) Now() time.Time {
+	self.mu.Lock()
+	defer self.mu.Unlock()
+	return self.now
+}
+
+// After implements [dotenv.Clock]. Returned channel receives current time of
+// fake clock, when [Harness.Advance] moves it to now + d or later.
+func (self *Harness) After(d time.Duration) <-chan time.Time {
+	self.mu.Lock()
+	defer self.mu.Unlock()
+
+	c := make(chan time.Time, 1)
+	if d <= 0 {
+		c <- self.now
+		return c
+	}
+	self.timers = append(self.timers, fakeTimer{at: self.now.Add(d), c: c})
+	self.cond.Broadcast()
+	return c
+}
+
+// Advance moves fake clock forward by d and fires timers, which expired.
+func (self *Harness) Advance(d time.Duration) {
+	self.mu.Lock()
+	defer self.mu.Unlock()
+
+	self.now = self.now.Add(d)
+	self.timers = slices.DeleteFunc(self.timers, func(t fakeTimer) bool {
+		if t.at.After(self.now) {
+			return false
+		}
+		t.c <- self.now
+		return true
+	})
+}
+
+// WaitTimers blocks until fake clock has at least n pending timers, so tests
+// know code under test is waiting for the clock, like [dotenv.Loader.Watch]
+// does between polls.
+func (self *Harness) WaitTimers(n int) {
+	self.mu.Lock()
+	defer self.mu.Unlock()
+	for len(self.timers) < n {
+		self.cond.Wait()
+	}
+}
+
+// harnessFS implements [fs.FS] by FS of [Harness], guarded against concurrent
+// writes by its methods.
+type harnessFS struct {
+	h *Harness
+}
+
+func (self harnessFS) Open(name string) (fs.File, error) {
+	self.h.fsMu.RLock()
+	defer self.h.fsMu.RUnlock()
+	return self.h.FS.Open(name) //nolint:wrapcheck // return it as is
+}
+
+func (self harnessFS) Stat(name string) (fs.FileInfo, error) {
+	self.h.fsMu.RLock()
+	defer self.h.fsMu.RUnlock()
+	return self.h.FS.Stat(name) //nolint:wrapcheck // return it as is
+}
+
+func (self harnessFS) ReadDir(name string) ([]fs.DirEntry, error) {
+	self.h.fsMu.RLock()
+	defer self.h.fsMu.RUnlock()
+	return self.h.FS.ReadDir(name) //nolint:wrapcheck // return it as is
+}
+
+func (self harnessFS) ReadFile(name string) ([]byte, error) {
+	self.h.fsMu.RLock()
+	defer self.h.fsMu.RUnlock()
+	return self.h.FS.ReadFile(name) //nolint:wrapcheck // return it as is
+}
+
+// fsName converts absolute path name into a name of [fstest.MapFS].
+func fsName(name string) string {
+	name = strings.TrimPrefix(path.Clean("/"+name), "/")
+	if name == "" {
+		return "."
+	}
+	return name
+}
+
+// ProcessEnv is a fake process environment, which implements
+// [dotenv.ProcessEnv]. It's safe for concurrent use.
+type ProcessEnv struct {
+	mu   sync.RWMutex
+	vars map[string]string
+}
+
+// NewProcessEnv creates and returns [ProcessEnv] with a copy of vars.
+func NewProcessEnv(vars map[string]string) *ProcessEnv {
+	vars = maps.Clone(vars)
+	if vars == nil {
+		vars = make(map[string]string)
+	}
+	return &ProcessEnv{vars: vars}
+}
+
+func (self *ProcessEnv) LookupEnv(key string) (string, bool) {
+	self.mu.RLock()
+	defer self.mu.RUnlock()
+	v, ok := self.vars[key]
+	return v, ok
+}
+
+func (self *ProcessEnv) Setenv(key, value string) error {
+	self.mu.Lock()
+	defer self.mu.Unlock()
+	self.vars[key] = value
+	return nil
+}
+
+func (self *ProcessEnv) Unsetenv(key string) error {
+	self.mu.Lock()
+	defer self.mu.Unlock()
+	delete(self.vars, key)
+	return nil
+}
+
+func (self *ProcessEnv) Environ() []string {
+	self.mu.RLock()
+	defer self.mu.RUnlock()
+	environ := make([]string, 0, len(self.vars))
+	for _, k := range slices.Sorted(maps.Keys(self.vars)) {
+		environ = append(environ, k+"="+self.vars[k])
+	}
+	return environ
+}
+
+// Map returns a copy of env vars as a map.
+func (self *ProcessEnv) Map() map[string]string {
+	self.mu.RLock()
+	defer self.mu.RUnlock()
+	return maps.Clone(self.vars)
+}
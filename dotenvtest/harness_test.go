@@ -0,0 +1,121 @@
+package dotenvtest
+
+import (
+	"context"
+	"io/fs"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	dotenv "github.com/dsh2dsh/expx-dotenv"
+)
+
+func TestHarness(t *testing.T) {
+	h := NewHarness()
+	h.WriteFile("/app/go.mod", "module app\n")
+	h.WriteFile("/app/.env", "TEST_VAR1=env\nTEST_VAR2=env\n")
+	h.Advance(time.Hour)
+	h.WriteFile("/app/.env.local", "TEST_VAR1=local\n")
+	h.Setenv("TEST_VAR2", "process")
+
+	fi, err := fs.Stat(h.FS, "app/.env.local")
+	require.NoError(t, err)
+	assert.Equal(t, h.Now(), fi.ModTime())
+	assert.Equal(t, time.Date(2000, time.January, 1, 1, 0, 0, 0, time.UTC),
+		h.Now())
+
+	env := h.Loader()
+	scoped, err := env.For("/app")
+	require.NoError(t, err)
+	assert.Equal(t, "local", scoped.Getenv("TEST_VAR1"))
+	assert.Equal(t, "process", scoped.Getenv("TEST_VAR2"))
+
+	h = NewHarness()
+	h.WriteFile("/.env", "TEST_VAR1=root\n")
+	h.Setenv("TEST_VAR2", "process")
+	require.NoError(t, h.Loader().Load())
+	assert.Equal(t, map[string]string{
+		"TEST_VAR1": "root",
+		"TEST_VAR2": "process",
+	}, h.Env.Map())
+	_, ok := os.LookupEnv("TEST_VAR1")
+	assert.False(t, ok)
+
+	h.RemoveFile("/.env")
+	assert.Empty(t, h.FS)
+}
+
+func TestHarness_Watch(t *testing.T) {
+	h := NewHarness()
+	h.WriteFile("/.env", "TEST_VAR1=first\n")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	changes := make(chan dotenv.Changes)
+	watchErr := make(chan error, 1)
+	env := h.Loader().WithPollInterval(time.Second)
+	go func() {
+		watchErr <- env.Watch(ctx, func(c dotenv.Changes) error {
+			changes <- c
+			return nil
+		})
+	}()
+
+	h.WaitTimers(1)
+	assert.Equal(t, "first", h.Env.Map()["TEST_VAR1"])
+
+	h.Advance(time.Second)
+	h.WaitTimers(1)
+	h.WriteFile("/.env", "TEST_VAR1=second\n")
+	h.Advance(time.Second)
+	h.WaitTimers(2)
+	h.Advance(dotenv.WatchDelay)
+
+	c := <-changes
+	require.NoError(t, c.Err)
+	assert.Equal(t, []dotenv.KeyDiff{
+		{Key: "TEST_VAR1", Kind: dotenv.DiffChanged, Old: "first", New: "second"},
+	}, c.Keys)
+	assert.Equal(t, "second", h.Env.Map()["TEST_VAR1"])
+
+	cancel()
+	require.ErrorIs(t, <-watchErr, context.Canceled)
+}
+
+func TestHarness_After(t *testing.T) {
+	h := NewHarness()
+	start := h.Now()
+	assert.Equal(t, start, <-h.After(0))
+
+	c := h.After(time.Minute)
+	h.WaitTimers(1)
+	h.Advance(time.Second)
+	select {
+	case <-c:
+		t.Fatal("unexpected timer")
+	default:
+	}
+
+	h.Advance(time.Minute)
+	assert.Equal(t, start.Add(time.Minute+time.Second), <-c)
+	h.WaitTimers(0)
+}
+
+func TestProcessEnv(t *testing.T) {
+	vars := map[string]string{"B": "2", "A": "1"}
+	env := NewProcessEnv(vars)
+	require.NoError(t, env.Setenv("C", "3"))
+	require.NoError(t, env.Unsetenv("B"))
+	assert.Equal(t, []string{"A=1", "C=3"}, env.Environ())
+	assert.Equal(t, map[string]string{"B": "2", "A": "1"}, vars)
+
+	v, ok := env.LookupEnv("A")
+	assert.True(t, ok)
+	assert.Equal(t, "1", v)
+	_, ok = env.LookupEnv("B")
+	assert.False(t, ok)
+}
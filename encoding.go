@@ -0,0 +1,92 @@
+package dotenv
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"unicode/utf16"
+	"unicode/utf8"
+)
+
+// ErrNotUTF8 returned by [Loader.Load] if a .env file isn't UTF-8 encoded and
+// transcoding isn't enabled by [Loader.WithTranscoding].
+var ErrNotUTF8 = errors.New("file is not UTF-8")
+
+var (
+	bomUTF8    = []byte{0xef, 0xbb, 0xbf}
+	bomUTF16LE = []byte{0xff, 0xfe}
+	bomUTF16BE = []byte{0xfe, 0xff}
+)
+
+// WithTranscoding configures [Loader.Load] to transcode .env files from
+// UTF-16 and ISO-8859-1 into UTF-8, instead of returning an error wrapping
+// [ErrNotUTF8]. Windows tools like to write these encodings. UTF-16 is
+// detected by its BOM or by zero bytes of ASCII chars, any other not UTF-8 file
+// is ISO-8859-1.
+func (self *Loader) WithTranscoding() *Loader {
+	self.transcoding = true
+	return self
+}
+
+// toUTF8 returns b, read from fname, as UTF-8 without BOM, transcoding it if
+// enabled, or an error.
+func (self *Loader) toUTF8(fname string, b []byte) ([]byte, error) {
+	if b, ok := bytes.CutPrefix(b, bomUTF8); ok {
+		if !utf8.Valid(b) {
+			return nil, fmt.Errorf("%w: %v has invalid UTF-8", ErrNotUTF8, fname)
+		}
+		return b, nil
+	}
+
+	enc := detectEncoding(b)
+	if enc == "" {
+		return b, nil
+	} else if !self.transcoding {
+		return nil, fmt.Errorf("%w: %v looks like %v", ErrNotUTF8, fname, enc)
+	}
+
+	switch enc {
+	case "UTF-16LE":
+		return decodeUTF16(bytes.TrimPrefix(b, bomUTF16LE), binary.LittleEndian),
+			nil
+	case "UTF-16BE":
+		return decodeUTF16(bytes.TrimPrefix(b, bomUTF16BE), binary.BigEndian), nil
+	}
+	return decodeLatin1(b), nil
+}
+
+// detectEncoding returns name of encoding of b or empty string, if it's UTF-8.
+func detectEncoding(b []byte) string {
+	switch {
+	case bytes.HasPrefix(b, bomUTF16LE):
+		return "UTF-16LE"
+	case bytes.HasPrefix(b, bomUTF16BE):
+		return "UTF-16BE"
+	case len(b) >= 2 && len(b)%2 == 0 && b[0] != 0 && b[1] == 0:
+		return "UTF-16LE"
+	case len(b) >= 2 && len(b)%2 == 0 && b[0] == 0 && b[1] != 0:
+		return "UTF-16BE"
+	case !utf8.Valid(b):
+		return "ISO-8859-1"
+	}
+	return ""
+}
+
+// decodeUTF16 returns UTF-16 encoded b, with byte order order, as UTF-8.
+func decodeUTF16(b []byte, order binary.ByteOrder) []byte {
+	units := make([]uint16, len(b)/2)
+	for i := range units {
+		units[i] = order.Uint16(b[2*i:])
+	}
+	return []byte(string(utf16.Decode(units)))
+}
+
+// decodeLatin1 returns ISO-8859-1 encoded b as UTF-8.
+func decodeLatin1(b []byte) []byte {
+	buf := make([]byte, 0, len(b)+len(b)/2)
+	for _, c := range b {
+		buf = utf8.AppendRune(buf, rune(c))
+	}
+	return buf
+}
@@ -0,0 +1,72 @@
+package dotenv
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoader_WithTranscoding(t *testing.T) {
+	tests := []struct {
+		name     string
+		content  []byte
+		encoding string
+	}{
+		{
+			name:    "UTF-8",
+			content: []byte("FOO=café\n"),
+		},
+		{
+			name:    "UTF-8 with BOM",
+			content: []byte("\xef\xbb\xbfFOO=café\n"),
+		},
+		{
+			name:     "ISO-8859-1",
+			content:  []byte("FOO=caf\xe9\n"),
+			encoding: "ISO-8859-1",
+		},
+		{
+			name:     "UTF-16LE with BOM",
+			content:  []byte("\xff\xfeF\x00O\x00O\x00=\x00c\x00a\x00f\x00\xe9\x00\n\x00"),
+			encoding: "UTF-16LE",
+		},
+		{
+			name:     "UTF-16BE with BOM",
+			content:  []byte("\xfe\xff\x00F\x00O\x00O\x00=\x00c\x00a\x00f\x00\xe9\x00\n"),
+			encoding: "UTF-16BE",
+		},
+		{
+			name:     "UTF-16LE without BOM",
+			content:  []byte("F\x00O\x00O\x00=\x00c\x00a\x00f\x00\xe9\x00\n\x00"),
+			encoding: "UTF-16LE",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fname := filepath.Join(t.TempDir(), ".env")
+			require.NoError(t, os.WriteFile(fname, tt.content, 0o600))
+
+			env := New()
+			if tt.encoding != "" {
+				_, err := env.readFile(fname)
+				require.ErrorIs(t, err, ErrNotUTF8)
+				assert.ErrorContains(t, err, fname+" looks like "+tt.encoding)
+			}
+
+			assert.Same(t, env, env.WithTranscoding())
+			vars, err := env.readFile(fname)
+			require.NoError(t, err)
+			assert.Equal(t, map[string]string{"FOO": "café"}, vars)
+		})
+	}
+}
+
+func TestLoader_toUTF8_invalidWithBOM(t *testing.T) {
+	_, err := New().WithTranscoding().toUTF8(".env",
+		[]byte("\xef\xbb\xbfFOO=caf\xe9\n"))
+	require.ErrorIs(t, err, ErrNotUTF8)
+}
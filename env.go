@@ -0,0 +1,170 @@
+package dotenv
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// essentialEnv contains env vars, which [Loader.EnvironFor] passes to any
+// subprocess, because most of programs expect them.
+var essentialEnv = []string{
+	"HOME", "LANG", "LC_ALL", "SYSTEMROOT", "TERM", "TMPDIR", "TZ", "USER",
+}
+
+// WithoutSetenv configures [Loader.Load] to never call [os.Setenv]. Loaded env
+// vars are kept in internal store of [Loader] and application reads them
+// using [Loader.Getenv] and [Loader.LookupEnv] only. Env vars, which already
+// exist in the process environment, still have priority.
+func (self *Loader) WithoutSetenv() *Loader {
+	self.noSetenv = true
+	return self
+}
+
+// setenvMu serializes applying of env vars by [Loader.Load], configured by
+// [Loader.WithSerializedSetenv], with reading of them by any [Loader].
+var setenvMu sync.RWMutex
+
+// WithSerializedSetenv configures [Loader.Load] to apply env vars of every .env
+// file under a package-level lock, which [Loader.Getenv] and
+// [Loader.LookupEnv] of any [Loader] share. [os.Setenv] isn't safe against
+// concurrent readers of the environment in some scenarios, like cgo code
+// reading environ.
+//
+// The concurrency model is:
+//
+//   - Load of the same [Loader] must not be called concurrently, with or
+//     without this option.
+//   - Getenv and LookupEnv are safe to call concurrently with Load of any
+//     [Loader], and they never observe a .env file applied partially, if Load
+//     is configured by this option.
+//   - Other code, which calls [os.Getenv] or [os.Environ] directly, doesn't
+//     take the lock.
+func (self *Loader) WithSerializedSetenv() *Loader {
+	self.serializeSetenv = true
+	return self
+}
+
+// WithHermetic configures [Loader] to ignore the process environment
+// entirely, except env vars from allow and essential ones, like PATH, HOME,
+// TMPDIR and so on. The environment is built purely from loaded .env files, so
+// stray exports on developer machines don't change results of tests and
+// builds. Allow-listed env vars of the process environment still have
+// priority.
+//
+// It implies [Loader.WithoutSetenv], because values of the process environment
+// are ambient by definition. Read env vars using [Loader.Getenv],
+// [Loader.LookupEnv] or pass them to subprocesses using
+// [Loader.EnvironFor].
+func (self *Loader) WithHermetic(allow ...string) *Loader {
+	self.hermetic, self.noSetenv = true, true
+	self.hermeticAllow = slices.Concat(essentialEnv, []string{"PATH"}, allow)
+	return self
+}
+
+// Getenv returns value of env var named key, like [os.Getenv] does. If Load
+// couldn't set env var, because [os.Setenv] is unavailable or the environment
+// is read-only (wasip1, some sandboxes), it returns value from internal store
+// of [Loader]. So programs in those sandboxes can still read loaded env vars
+// using Getenv. See also [Loader.WithoutSetenv].
+func (self *Loader) Getenv(key string) string {
+	v, _ := self.LookupEnv(key)
+	return v
+}
+
+// LookupEnv returns value of env var named key and true, if it exists, like
+// [os.LookupEnv] does. It looks in internal store of [Loader] first and in the
+// process environment next. See [Loader.Getenv] for details. In hermetic mode
+// it looks in allow-listed env vars of the process environment only, see
+// [Loader.WithHermetic].
+func (self *Loader) LookupEnv(key string) (string, bool) {
+	setenvMu.RLock()
+	defer setenvMu.RUnlock()
+	return self.lookupEnv(key)
+}
+
+// lookupEnv implements [Loader.LookupEnv] without locking.
+func (self *Loader) lookupEnv(key string) (string, bool) {
+	if v, ok := self.vars[key]; ok {
+		return v, true
+	} else if !self.ambientEnv(key) {
+		return "", false
+	}
+	return self.procEnv.LookupEnv(key)
+}
+
+// lookupPending is like lookupEnv, but looks in vars of current Load, instead
+// of vars of previous one.
+func (self *Loader) lookupPending(key string) (string, bool) {
+	if v, ok := self.pending[key]; ok {
+		return v, true
+	} else if !self.ambientEnv(key) {
+		return "", false
+	}
+	return self.procEnv.LookupEnv(key)
+}
+
+// ambientEnv returns true if env var key of the process environment is visible
+// for [Loader].
+func (self *Loader) ambientEnv(key string) bool {
+	return !self.hermetic || slices.Contains(self.hermeticAllow, key)
+}
+
+// environ returns merged environment as a map: the process environment and
+// internal store of [Loader].
+func (self *Loader) environ() map[string]string {
+	setenvMu.RLock()
+	defer setenvMu.RUnlock()
+
+	vars := environMap(self.procEnv.Environ())
+	maps.DeleteFunc(vars, func(k, v string) bool { return !self.ambientEnv(k) })
+	for k, v := range self.vars {
+		vars[k] = v
+	}
+	return vars
+}
+
+// setenv sets env var key to value, loaded from fname. If it can't, it keeps
+// key and value in internal store, see [Loader.Getenv], and adds a warning.
+func (self *Loader) setenv(fname, key, value string) {
+	if !self.noSetenv {
+		err := self.setenvFn(key, value)
+		if err == nil {
+			return
+		}
+		self.warnings = append(self.warnings,
+			fmt.Errorf("set env var %v from %v: %w", key, fname, err))
+	}
+
+	if self.pending == nil {
+		self.pending = make(map[string]string)
+	}
+	self.pending[key] = value
+}
+
+// EnvironFor returns a minimal environment for a subprocess cmdName, like
+// [os.Environ] does, which contains allow-listed env vars only, plus essential
+// ones like HOME, LANG, TMPDIR and so on. PATH is essential too, if cmdName has
+// no path separators and must be looked up. Values are from
+// [Loader.LookupEnv]. It helps to follow least-privilege when spawning
+// subprocesses:
+//
+//	cmd := exec.Command("pg_dump")
+//	cmd.Env = env.EnvironFor(cmd.Path, []string{"PGHOST", "PGPASSWORD"})
+func (self *Loader) EnvironFor(cmdName string, allow []string) []string {
+	keys := slices.Concat(essentialEnv, allow)
+	if !strings.ContainsFunc(cmdName, isPathSeparator) {
+		keys = append(keys, "PATH")
+	}
+	slices.Sort(keys)
+
+	environ := make([]string, 0, len(keys))
+	for _, key := range slices.Compact(keys) {
+		if v, ok := self.LookupEnv(key); ok {
+			environ = append(environ, key+"="+v)
+		}
+	}
+	return environ
+}
@@ -0,0 +1,262 @@
+package dotenv
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoader_Getenv_readOnly(t *testing.T) {
+	changeDir(t, "testdata")
+	restoreEnvVars(t)
+	t.Setenv(allEnvVars[1], "from env")
+
+	errReadOnly := errors.New("read-only environment")
+	env := New()
+	env.setenvFn = func(key, value string) error { return errReadOnly }
+	require.NoError(t, env.Load())
+
+	_, ok := os.LookupEnv(allEnvVars[0])
+	assert.False(t, ok)
+	assert.Equal(t, "testdata", env.Getenv(allEnvVars[0]))
+	assert.Equal(t, "from env", env.Getenv(allEnvVars[1]))
+	assert.Equal(t, "", env.Getenv("TEST_VAR_NOT_EXISTS"))
+
+	warnings := env.Result().Warnings
+	require.Len(t, warnings, 1)
+	require.ErrorIs(t, warnings[0], errReadOnly)
+
+	env.setenvFn = os.Setenv
+	require.NoError(t, env.Load())
+	assert.Empty(t, env.vars)
+	assert.Equal(t, "testdata", os.Getenv(allEnvVars[0]))
+	assert.Equal(t, []string{allEnvVars[1]}, env.Result().ExistingKeys)
+}
+
+func TestLoader_WithoutSetenv(t *testing.T) {
+	changeDir(t, "testdata")
+	restoreEnvVars(t)
+	t.Setenv(allEnvVars[1], "from env")
+
+	env := New()
+	assert.Same(t, env, env.WithoutSetenv())
+	env.setenvFn = func(key, value string) error {
+		t.Fatalf("unexpected setenv %v=%v", key, value)
+		return nil
+	}
+	require.NoError(t, env.Load())
+	assert.Empty(t, env.Result().Warnings)
+
+	_, ok := os.LookupEnv(allEnvVars[0])
+	assert.False(t, ok)
+
+	v, ok := env.LookupEnv(allEnvVars[0])
+	assert.True(t, ok)
+	assert.Equal(t, "testdata", v)
+
+	v, ok = env.LookupEnv(allEnvVars[1])
+	assert.True(t, ok)
+	assert.Equal(t, "from env", v)
+
+	_, ok = env.LookupEnv("TEST_VAR_NOT_EXISTS")
+	assert.False(t, ok)
+}
+
+func TestLoader_WithoutSetenv_reload(t *testing.T) {
+	dir := t.TempDir()
+	fname := filepath.Join(dir, ".env")
+	require.NoError(t, os.WriteFile(fname, []byte("TEST_VAR1=two\n"), 0o600))
+	changeDir(t, dir)
+	restoreEnvVars(t)
+
+	env := New().WithRootDir(".").WithoutSetenv()
+	require.NoError(t, env.Load())
+	assert.Equal(t, "two", env.Getenv(allEnvVars[0]))
+
+	require.NoError(t, os.WriteFile(fname, []byte("TEST_VAR1=three\n"), 0o600))
+	require.NoError(t, env.Load())
+	assert.Equal(t, "three", env.Getenv(allEnvVars[0]))
+	assert.Empty(t, env.Result().ExistingKeys)
+
+	require.NoError(t, env.RunIsolated(context.Background(), func(e Env) error {
+		assert.Equal(t, "three", e.Getenv(allEnvVars[0]))
+		return nil
+	}))
+
+	require.NoError(t, os.WriteFile(fname, nil, 0o600))
+	require.NoError(t, env.Load())
+	_, ok := env.LookupEnv(allEnvVars[0])
+	assert.False(t, ok)
+}
+
+func TestLoader_EnvironFor(t *testing.T) {
+	t.Setenv("HOME", "/home/test")
+	t.Setenv("PATH", "/bin")
+	t.Setenv("TEST_SECRET", "secret")
+	t.Setenv("TEST_ALLOWED", "allowed")
+
+	env := New().WithoutSetenv()
+	env.vars = map[string]string{"TEST_LOADED": "loaded"}
+
+	environ := env.EnvironFor("psql", []string{
+		"TEST_ALLOWED", "TEST_LOADED", "TEST_NOT_EXISTS", "TEST_ALLOWED",
+	})
+	assert.Contains(t, environ, "HOME=/home/test")
+	assert.Contains(t, environ, "PATH=/bin")
+	assert.Contains(t, environ, "TEST_ALLOWED=allowed")
+	assert.Contains(t, environ, "TEST_LOADED=loaded")
+	assert.NotContains(t, environ, "TEST_SECRET=secret")
+	assert.True(t, slices.IsSorted(environ))
+	assert.Len(t, slices.Compact(slices.Clone(environ)), len(environ))
+
+	environ = env.EnvironFor("/usr/bin/psql", nil)
+	assert.Contains(t, environ, "HOME=/home/test")
+	assert.NotContains(t, environ, "PATH=/bin")
+}
+
+func TestLoader_WithHermetic(t *testing.T) {
+	changeDir(t, "testdata")
+	restoreEnvVars(t)
+	t.Setenv(allEnvVars[0], "stray")
+	t.Setenv(allEnvVars[1], "allowed")
+	t.Setenv("TEST_STRAY", "stray")
+	t.Setenv("HOME", "/home/test")
+
+	env := New()
+	assert.Same(t, env, env.WithHermetic(allEnvVars[1]))
+	env.setenvFn = func(key, value string) error {
+		t.Fatalf("unexpected setenv %v=%v", key, value)
+		return nil
+	}
+	require.NoError(t, env.Load())
+
+	assert.Equal(t, "testdata", env.Getenv(allEnvVars[0]))
+	assert.Equal(t, "stray", os.Getenv(allEnvVars[0]))
+	assert.Equal(t, "allowed", env.Getenv(allEnvVars[1]))
+	assert.Equal(t, "/home/test", env.Getenv("HOME"))
+
+	_, ok := env.LookupEnv("TEST_STRAY")
+	assert.False(t, ok)
+
+	vars := env.environ()
+	assert.Equal(t, "testdata", vars[allEnvVars[0]])
+	assert.Equal(t, "/home/test", vars["HOME"])
+	assert.NotContains(t, vars, "TEST_STRAY")
+}
+
+func TestLoader_WithSerializedSetenv(t *testing.T) {
+	tests := []struct {
+		name   string
+		before func(env *Loader)
+		locked bool
+	}{
+		{
+			name:   "serialized",
+			before: func(env *Loader) { env.WithSerializedSetenv() },
+			locked: true,
+		},
+		{
+			name: "without lock",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			changeDir(t, "testdata/g")
+			restoreEnvVars(t)
+
+			env := New()
+			if tt.before != nil {
+				tt.before(env)
+			}
+
+			var locked []bool
+			env.setenvFn = func(key, value string) error {
+				ok := setenvMu.TryRLock()
+				if ok {
+					setenvMu.RUnlock()
+				}
+				locked = append(locked, !ok)
+				return os.Setenv(key, value)
+			}
+
+			require.NoError(t, env.Load())
+			assert.Equal(t, []bool{tt.locked, tt.locked}, locked)
+			assert.Equal(t, "local", env.Getenv(allEnvVars[0]))
+		})
+	}
+}
+
+func TestLoader_WithSerializedSetenv_concurrent(t *testing.T) {
+	changeDir(t, "testdata/g")
+	restoreEnvVars(t)
+
+	env := New().WithoutSetenv().WithSerializedSetenv()
+	reader := New()
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for range 100 {
+			_ = reader.Getenv(allEnvVars[0])
+			_ = env.Getenv(allEnvVars[0])
+		}
+	}()
+
+	for range 10 {
+		require.NoError(t, env.Load())
+	}
+	<-done
+	assert.Equal(t, "local", env.Getenv(allEnvVars[0]))
+}
+
+func TestLoader_WithoutSetenv_concurrentReload(t *testing.T) {
+	changeDir(t, "testdata/g")
+	restoreEnvVars(t)
+
+	env := New().WithoutSetenv()
+	require.NoError(t, env.Load())
+
+	done := make(chan []string)
+	go func() {
+		var got []string
+		for range 1000 {
+			if v := env.Getenv(allEnvVars[0]); v != "local" {
+				got = append(got, v)
+			}
+		}
+		done <- got
+	}()
+
+	for {
+		require.NoError(t, env.Load())
+		select {
+		case got := <-done:
+			assert.Empty(t, got)
+			return
+		default:
+		}
+	}
+}
+
+func TestWithProcessEnv(t *testing.T) {
+	changeDir(t, "testdata/g")
+	restoreEnvVars(t)
+
+	procEnv := mapProcessEnv{"APP_ENV": "test", allEnvVars[1]: "process"}
+	env := New(WithProcessEnv(procEnv), WithEnvVarName("APP_ENV"))
+	require.NoError(t, env.Load())
+	assert.Equal(t, mapProcessEnv{
+		"APP_ENV":     "test",
+		allEnvVars[0]: "local",
+		allEnvVars[1]: "process",
+	}, procEnv)
+	assert.Equal(t, "process", env.Getenv(allEnvVars[1]))
+	assert.Equal(t, "local", env.environ()[allEnvVars[0]])
+	assert.Empty(t, os.Getenv(allEnvVars[0]))
+}
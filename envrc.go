@@ -0,0 +1,90 @@
+package dotenv
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// envrcFile is a name of direnv file.
+const envrcFile = ".envrc"
+
+// envrcDotenvRe matches dotenv directives of .envrc, like "dotenv" or
+// "dotenv_if_exists .env.local".
+var envrcDotenvRe = regexp.MustCompile(
+	`^\s*(?:dotenv|dotenv_if_exists)(?:\s+(\S+))?\s*(?:#.*)?$`)
+
+// WithEnvrc configures [Loader.Load] to recognize .envrc files of direnv, which
+// are used by Nix based tools like devenv and Flox, together with lines like:
+//
+//	use flake
+//	dotenv_if_exists .env.local
+//	dotenv
+//
+// If a dir contains .envrc with dotenv directives, .env files from these
+// directives are loaded instead of configured cascade, with the same
+// precedence direnv gives them: the last directive wins. So Go services inside
+// Nix managed repos resolve the same environment as the developer shell. The
+// default file of "dotenv" is .env, like direnv and devenv use.
+//
+// Directives with variables or paths outside of the dir are ignored. Dirs
+// without such .envrc use configured cascade.
+func (self *Loader) WithEnvrc() *Loader {
+	self.envrc = true
+	return self
+}
+
+// envrcFiles returns names of .env files from dotenv directives of .envrc in
+// dir, in cascade order. It returns nil, if it isn't enabled or dir has no
+// such .envrc.
+func (self *Loader) envrcFiles(dir string) ([]string, error) {
+	if !self.envrc {
+		return nil, nil
+	} else if exists, err := self.FileExistsInDir(dir, envrcFile); err != nil {
+		return nil, err
+	} else if !exists {
+		return nil, nil
+	}
+
+	fname := filepath.Join(dir, envrcFile)
+	b, err := self.readFileBytes(fname)
+	if err != nil {
+		return nil, fmt.Errorf("read %v: %w", fname, err)
+	}
+	return parseEnvrc(b), nil
+}
+
+// parseEnvrc returns names of .env files from dotenv directives of .envrc
+// content b, from the last to the first one.
+func parseEnvrc(b []byte) []string {
+	var files []string
+	scanner := bufio.NewScanner(bytes.NewReader(b))
+	for scanner.Scan() {
+		m := envrcDotenvRe.FindStringSubmatch(scanner.Text())
+		if m == nil {
+			continue
+		}
+
+		name := strings.Trim(m[1], `"'`)
+		if name == "" {
+			name = ".env"
+		}
+		name = filepath.Clean(strings.TrimPrefix(name, "./"))
+		if !strings.ContainsAny(name, "$`") && filepath.IsLocal(name) {
+			files = append(files, name)
+		}
+	}
+
+	slices.Reverse(files)
+	unique := files[:0]
+	for _, name := range files {
+		if !slices.Contains(unique, name) {
+			unique = append(unique, name)
+		}
+	}
+	return unique
+}
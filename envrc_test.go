@@ -0,0 +1,52 @@
+package dotenv
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseEnvrc(t *testing.T) {
+	files := parseEnvrc([]byte(`use flake
+dotenv
+dotenv_if_exists "./.env.local" # local overrides
+dotenv .env.$USER
+dotenv ../.env
+export FOO=bar
+dotenv
+`))
+	assert.Equal(t, []string{".env", ".env.local"}, files)
+	assert.Empty(t, parseEnvrc([]byte("use devenv\n")))
+}
+
+func TestLoader_WithEnvrc(t *testing.T) {
+	dir := t.TempDir()
+	writeFile := func(name, content string) {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name),
+			[]byte(content), 0o600))
+	}
+	writeFile(".envrc", "use flake\ndotenv .env.nix\ndotenv_if_exists .env.dev\n")
+	writeFile(".env.nix", "TEST_VAR1=nix\nTEST_VAR2=nix\n")
+	writeFile(".env.dev", "TEST_VAR1=dev\n")
+	writeFile(".env", "TEST_VAR1=env\n")
+	changeDir(t, dir)
+	restoreEnvVars(t)
+
+	env := New().WithRootDir(".")
+	require.NoError(t, env.Load())
+	assert.Equal(t, "env", os.Getenv(allEnvVars[0]))
+
+	restoreEnvVars(t)
+	assert.Same(t, env, env.WithEnvrc())
+	require.NoError(t, env.Load())
+	assert.Equal(t, "dev", os.Getenv(allEnvVars[0]))
+	assert.Equal(t, "nix", os.Getenv(allEnvVars[1]))
+	assert.Equal(t, []SourceRef{
+		{Kind: SourceEnv},
+		{Kind: SourceFile, Name: ".env.dev"},
+		{Kind: SourceFile, Name: ".env.nix"},
+	}, env.Precedence())
+}
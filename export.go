@@ -0,0 +1,120 @@
+package dotenv
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"slices"
+	"strings"
+)
+
+// ErrInvalidTFVar returned by [Loader.WriteTFVars] if name of env var with
+// prefix "TF_VAR_" isn't a valid name of Terraform input variable.
+var ErrInvalidTFVar = errors.New("invalid Terraform variable name")
+
+// tfVarPrefix is a prefix of env vars, which Terraform reads as input
+// variables.
+const tfVarPrefix = "TF_VAR_"
+
+// tfvarsReplacer escapes a string for a quoted HCL string.
+var tfvarsReplacer = strings.NewReplacer(
+	`\`, `\\`,
+	`"`, `\"`,
+	"\n", `\n`,
+	"\r", `\r`,
+	"\t", `\t`,
+	"${", "$${",
+	"%{", "%%{",
+)
+
+// WriteTFVars writes env vars with prefix "TF_VAR_", defined by .env files of
+// last [Loader.Load], into w in format of terraform.tfvars. The prefix is
+// removed, like Terraform does it for env vars, so
+//
+//	TF_VAR_region=eu-west-1
+//
+// becomes
+//
+//	region = "eu-west-1"
+//
+// It contains effective values, like [Loader.WriteSnapshot] does, and values of
+// secret keys can be encrypted, see [Loader.WithExportEncryption]. So
+// infrastructure tooling, sharing a repo with Go services, consumes the same
+// .env files without duplication.
+//
+// Every value is written as HCL string, so Terraform variables of other types,
+// like number or list, must convert it. Names must be HCL identifiers: a
+// letter or underscore, followed by letters, digits, underscores or dashes.
+// Otherwise it returns an error wrapping [ErrInvalidTFVar] and writes nothing.
+func (self *Loader) WriteTFVars(w io.Writer) error {
+	if self.loaded == nil {
+		return ErrNotLoaded
+	}
+
+	vars, err := self.exportVars()
+	if err != nil {
+		return fmt.Errorf("write tfvars: %w", err)
+	}
+
+	var sb strings.Builder
+	for _, k := range slices.Sorted(maps.Keys(vars)) {
+		name, ok := strings.CutPrefix(k, tfVarPrefix)
+		if !ok || name == "" {
+			continue
+		} else if !hclIdentifier(name) {
+			return fmt.Errorf("write tfvars: %w: %q", ErrInvalidTFVar, k)
+		}
+		fmt.Fprintf(&sb, "%v = \"%v\"\n", name, tfvarsReplacer.Replace(vars[k]))
+	}
+
+	if _, err := io.WriteString(w, sb.String()); err != nil {
+		return fmt.Errorf("write tfvars: %w", err)
+	}
+	return nil
+}
+
+// hclIdentifier returns true if s is a valid HCL identifier.
+func hclIdentifier(s string) bool {
+	for i, r := range s {
+		switch {
+		case r == '_', 'a' <= r && r <= 'z', 'A' <= r && r <= 'Z':
+		case i > 0 && (r == '-' || '0' <= r && r <= '9'):
+		default:
+			return false
+		}
+	}
+	return s != ""
+}
+
+// WriteCDKContext writes env vars, defined by .env files of last
+// [Loader.Load], into w as JSON object of cdk.context.json, sorted by key:
+//
+//	{
+//	  "REGION": "eu-west-1"
+//	}
+//
+// It contains effective values, like [Loader.WriteSnapshot] does, except
+// secrets, because cdk.context.json is usually committed: keys, loaded from
+// files with secrets, keys, matching patterns of
+// [Loader.WithExportEncryption], and encrypted values are skipped. AWS CDK apps
+// can read them by app.node.tryGetContext("REGION").
+func (self *Loader) WriteCDKContext(w io.Writer) error {
+	if self.loaded == nil {
+		return ErrNotLoaded
+	}
+
+	vars := maps.Clone(self.loaded)
+	maps.DeleteFunc(vars, func(k, v string) bool {
+		return self.exportSecret(k) || strings.HasPrefix(v, encPrefix)
+	})
+
+	enc := json.NewEncoder(w)
+	enc.SetEscapeHTML(false)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(vars); err != nil {
+		return fmt.Errorf("write cdk context: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,62 @@
+package dotenv
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoader_WriteTFVars(t *testing.T) {
+	var sb strings.Builder
+	env := New().WithoutSetenv()
+	require.ErrorIs(t, env.WriteTFVars(&sb), ErrNotLoaded)
+
+	require.NoError(t, env.LoadString(`
+TF_VAR_region=eu-west-1
+TF_VAR_tags='{"a" = "${b}"}'
+TF_VAR_=empty
+APP_NAME=app
+`))
+	require.NoError(t, env.WriteTFVars(&sb))
+	assert.Equal(t, `region = "eu-west-1"
+tags = "{\"a\" = \"$${b}\"}"
+`, sb.String())
+
+	for _, name := range []string{"1region", "db.host", "a b"} {
+		require.NoError(t, env.LoadString("TF_VAR_"+name+"=x\n"))
+		sb.Reset()
+		require.ErrorIs(t, env.WriteTFVars(&sb), ErrInvalidTFVar, name)
+		assert.Empty(t, sb.String())
+	}
+
+	require.NoError(t, env.LoadString("TF_VAR__db_2=x\n"))
+	sb.Reset()
+	require.NoError(t, env.WriteTFVars(&sb))
+	assert.Equal(t, "_db_2 = \"x\"\n", sb.String())
+}
+
+func TestLoader_WriteCDKContext(t *testing.T) {
+	var sb strings.Builder
+	env := New().WithoutSetenv()
+	require.ErrorIs(t, env.WriteCDKContext(&sb), ErrNotLoaded)
+
+	require.NoError(t, env.LoadString("REGION=eu-west-1\nURL='a?b=1&c=<2>'\n"))
+	require.NoError(t, env.WriteCDKContext(&sb))
+	assert.Equal(t, `{
+  "REGION": "eu-west-1",
+  "URL": "a?b=1&c=<2>"
+}
+`, sb.String())
+
+	env.WithExportEncryption(nil, "*_PASSWORD")
+	require.NoError(t, env.LoadString(
+		"REGION=eu-west-1\nDB_PASSWORD=secret\nAPI_KEY=enc:v1:abc\n"))
+	sb.Reset()
+	require.NoError(t, env.WriteCDKContext(&sb))
+	assert.Equal(t, `{
+  "REGION": "eu-west-1"
+}
+`, sb.String())
+}
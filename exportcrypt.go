@@ -0,0 +1,74 @@
+package dotenv
+
+import (
+	"fmt"
+	"maps"
+	"path"
+	"slices"
+	"strings"
+)
+
+// WithExportEncryption configures [Loader.WriteSnapshot] and
+// [Loader.WriteTFVars] to encrypt values of secret keys using AES-256-GCM with key from kp, like [EncryptValue] does. So
+// snapshots and exports never become the weakest link of secret handling.
+// Secret keys are keys, matching any of patterns with syntax of [path.Match],
+// like "*_PASSWORD" or "*TOKEN*", and keys, loaded from files with secrets, see
+// [Loader.WithSecretsEnabled]:
+//
+//	env := dotenv.New().WithSecretsEnabled().
+//		WithExportEncryption(kp, "*_PASSWORD").
+//		WithKeyProvider(kp).
+//		WithSnapshotFallback(".env.snapshot")
+//
+// Encrypted values look like "enc:v1:..." and [Loader.Load] decrypts them, if
+// it's configured by [Loader.WithKeyProvider], including snapshot fallback.
+// Nil kp disables encryption.
+func (self *Loader) WithExportEncryption(kp KeyProvider,
+	patterns ...string,
+) *Loader {
+	self.exportKP, self.exportSecrets = kp, patterns
+	return self
+}
+
+// exportVars returns env vars of last Load for snapshot or export, with
+// encrypted values of secret keys, if it's configured by
+// [Loader.WithExportEncryption].
+func (self *Loader) exportVars() (map[string]string, error) {
+	if self.exportKP == nil {
+		return self.loaded, nil
+	}
+
+	var key []byte
+	vars := maps.Clone(self.loaded)
+	for _, k := range slices.Sorted(maps.Keys(vars)) {
+		v := vars[k]
+		if !self.exportSecret(k) || strings.HasPrefix(v, encPrefix) {
+			continue
+		} else if key == nil {
+			b, err := self.exportKP.Key()
+			if err != nil {
+				return nil, fmt.Errorf("get export key: %w", err)
+			}
+			key = b
+		}
+
+		encrypted, err := EncryptValue(key, k, v)
+		if err != nil {
+			return nil, fmt.Errorf("encrypt %v: %w", k, err)
+		}
+		vars[k] = encrypted
+	}
+	return vars, nil
+}
+
+// exportSecret returns true if value of key must be encrypted by exportVars.
+func (self *Loader) exportSecret(key string) bool {
+	if ref, ok := self.winners[key]; ok && ref.Kind != SourceEnv &&
+		strings.HasSuffix(ref.Name, secretsExt) {
+		return true
+	}
+	return slices.ContainsFunc(self.exportSecrets, func(pattern string) bool {
+		ok, _ := path.Match(pattern, key)
+		return ok
+	})
+}
@@ -0,0 +1,70 @@
+package dotenv
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoader_WithExportEncryption(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, ".env"),
+		[]byte("DB_PASSWORD=secret\nDB_HOST=localhost\nTF_VAR_api_token=token\n"),
+		0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "go.mod"), nil, 0o600))
+	changeDir(t, dir)
+
+	key := bytes.Repeat([]byte{1}, 32)
+	kp := KeyProviderFunc(func() ([]byte, error) { return key, nil })
+	env := New().WithoutSetenv().WithExportEncryption(kp, "*_PASSWORD", "*_token")
+	require.NoError(t, env.Load())
+
+	snapshot := filepath.Join(t.TempDir(), "snapshot.env")
+	require.NoError(t, env.WriteSnapshot(snapshot))
+	b := valueNoError[[]byte](t)(os.ReadFile(snapshot))
+	assert.Contains(t, string(b), `DB_HOST="localhost"`)
+	assert.Contains(t, string(b), `DB_PASSWORD="enc:v1:`)
+	assert.NotContains(t, string(b), "secret")
+
+	var sb strings.Builder
+	require.NoError(t, env.WriteTFVars(&sb))
+	assert.Contains(t, sb.String(), `api_token = "enc:v1:`)
+	sb.Reset()
+	require.NoError(t, env.WriteCDKContext(&sb))
+	assert.Contains(t, sb.String(), `"DB_HOST": "localhost"`)
+	assert.NotContains(t, sb.String(), "DB_PASSWORD")
+	assert.Equal(t, "secret", env.Getenv("DB_PASSWORD"))
+
+	require.NoError(t, os.Remove(filepath.Join(dir, ".env")))
+	restored := New().WithoutSetenv().WithKeyProvider(kp).
+		WithSnapshotFallback(snapshot)
+	require.NoError(t, restored.Load())
+	assert.Equal(t, "secret", restored.Getenv("DB_PASSWORD"))
+	assert.Equal(t, "localhost", restored.Getenv("DB_HOST"))
+	assert.Equal(t, "token", restored.Getenv("TF_VAR_api_token"))
+
+	errKey := errors.New("no key")
+	env.WithExportEncryption(KeyProviderFunc(func() ([]byte, error) {
+		return nil, errKey
+	}), "*")
+	require.ErrorIs(t, env.WriteSnapshot(snapshot), errKey)
+	require.ErrorIs(t, env.WriteTFVars(&sb), errKey)
+}
+
+func TestLoader_exportSecret(t *testing.T) {
+	env := New().WithExportEncryption(nil, "*_PASSWORD")
+	env.winners = map[string]SourceRef{
+		"API_KEY": {Kind: SourceFile, Name: ".env" + secretsExt},
+		"HOME":    {Kind: SourceEnv},
+	}
+	assert.True(t, env.exportSecret("API_KEY"))
+	assert.True(t, env.exportSecret("DB_PASSWORD"))
+	assert.False(t, env.exportSecret("HOME"))
+	assert.False(t, env.exportSecret("DB_HOST"))
+}
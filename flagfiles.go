@@ -0,0 +1,88 @@
+package dotenv
+
+import (
+	"maps"
+	"path/filepath"
+	"strconv"
+)
+
+// WithFeatureFlags configures [Loader] to load feature toggles from .flags
+// files, which live beside .env files in the dir, found by lookup, and follow
+// the same cascade:
+//
+//  1. .flags.{ENVIRONMENT_NAME}.local
+//  2. .flags.local
+//  3. .flags.{ENVIRONMENT_NAME}
+//  4. .flags
+//
+// They have the same syntax as .env files, like:
+//
+//	new_checkout=1
+//	legacy_export=false
+//
+// but toggles don't pollute the process environment and are available by
+// [Loader.Flag] only. The first file, which defines a toggle, wins. .flags
+// files don't make a dir found and aren't loaded, if no .env files were found.
+// Otherwise they're read like .env files, so pinned checksums, ownership and
+// other checks of .env files apply to them too, see
+// [Loader.WithPinnedChecksums].
+func (self *Loader) WithFeatureFlags() *Loader {
+	self.featureFlags = true
+	return self
+}
+
+// Flag returns true if feature toggle name is enabled by .flags files, loaded
+// by last [Loader.Load], see [Loader.WithFeatureFlags]. Values are parsed by
+// [strconv.ParseBool], so "1", "t", "true" and so on enable the toggle. Unknown
+// toggles and invalid values are disabled.
+func (self *Loader) Flag(name string) bool {
+	enabled, err := strconv.ParseBool(self.flags[name])
+	return err == nil && enabled
+}
+
+// Flags returns all feature toggles with their raw values, loaded by last
+// [Loader.Load], see [Loader.WithFeatureFlags].
+func (self *Loader) Flags() map[string]string { return maps.Clone(self.flags) }
+
+// flagFiles returns names of .flags files in cascade order.
+func (self *Loader) flagFiles() []string {
+	envName := self.envSuffix
+	if envName == "" {
+		return []string{".flags.local", ".flags"}
+	}
+
+	return []string{
+		".flags." + envName + ".local", ".flags.local",
+		".flags." + envName, ".flags",
+	}
+}
+
+// loadFlagFiles loads .flags files from dir with .env files, if it's
+// configured by [Loader.WithFeatureFlags].
+func (self *Loader) loadFlagFiles() error {
+	if !self.featureFlags {
+		return nil
+	}
+
+	self.flags = make(map[string]string)
+	for _, fname := range self.flagFiles() {
+		if exists, err := self.FileExistsInDir(self.envDir, fname); err != nil {
+			return err
+		} else if !exists {
+			continue
+		} else if self.envDir != "" {
+			fname = filepath.Join(self.envDir, fname)
+		}
+
+		flags, err := self.readFile(fname)
+		if err != nil {
+			return err
+		}
+		for k, v := range flags {
+			if _, ok := self.flags[k]; !ok {
+				self.flags[k] = v
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,115 @@
+package dotenv
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"os"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoader_WithFeatureFlags(t *testing.T) {
+	tests := []struct {
+		name   string
+		suffix string
+		expect map[string]bool
+	}{
+		{
+			name: "without environment",
+			expect: map[string]bool{
+				"new_checkout":  true,
+				"legacy_export": false,
+				"broken":        false,
+				"unknown":       false,
+			},
+		},
+		{
+			name:   "with environment",
+			suffix: "test",
+			expect: map[string]bool{
+				"new_checkout":  false,
+				"legacy_export": false,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			changeDir(t, "testdata/j")
+			restoreEnvVars(t)
+
+			env := New().WithEnvSuffix(tt.suffix)
+			assert.Same(t, env, env.WithFeatureFlags())
+			require.NoError(t, env.Load())
+			for name, enabled := range tt.expect {
+				assert.Equal(t, enabled, env.Flag(name), name)
+			}
+			assert.Equal(t, "env", os.Getenv(allEnvVars[0]))
+			_, ok := os.LookupEnv("new_checkout")
+			assert.False(t, ok)
+		})
+	}
+}
+
+func TestLoader_WithFeatureFlags_parentDir(t *testing.T) {
+	changeDir(t, "testdata/j/k")
+	restoreEnvVars(t)
+
+	env := New().WithFeatureFlags()
+	require.NoError(t, env.Load())
+	assert.True(t, env.Flag("new_checkout"))
+	assert.Equal(t, map[string]string{
+		"new_checkout":  "1",
+		"legacy_export": "false",
+		"broken":        "maybe",
+	}, env.Flags())
+}
+
+func TestLoader_WithFeatureFlags_WithFS(t *testing.T) {
+	restoreEnvVars(t)
+
+	fsys := newTestFS()
+	fsys[".flags"] = &fstest.MapFile{Data: []byte("new_checkout=1\n")}
+	fsys["app/.flags"] = &fstest.MapFile{Data: []byte("new_checkout=0\n")}
+
+	env := New().WithFS(fsys).WithFeatureFlags().WithoutSetenv()
+	require.NoError(t, env.Load())
+	assert.True(t, env.Flag("new_checkout"))
+
+	scoped, err := env.For("app")
+	require.NoError(t, err)
+	assert.Equal(t, "app", scoped.Getenv(allEnvVars[0]))
+}
+
+func TestLoader_WithFeatureFlags_checksum(t *testing.T) {
+	restoreEnvVars(t)
+
+	fsys := newTestFS()
+	fsys[".flags"] = &fstest.MapFile{Data: []byte("new_checkout=1\n")}
+	sums := make(map[string]string)
+	for _, fname := range []string{".env", ".flags"} {
+		sum := sha256.Sum256(fsys[fname].Data)
+		sums[fname] = hex.EncodeToString(sum[:])
+	}
+
+	env := New().WithFS(fsys).WithoutSetenv().WithFeatureFlags().
+		WithPinnedChecksums(map[string]string{".env": sums[".env"]})
+	require.ErrorIs(t, env.Load(), ErrChecksum)
+
+	env.WithPinnedChecksums(sums)
+	require.NoError(t, env.Load())
+	assert.True(t, env.Flag("new_checkout"))
+}
+
+func TestLoader_Flag_disabled(t *testing.T) {
+	changeDir(t, "testdata/j")
+	restoreEnvVars(t)
+
+	env := New()
+	require.NoError(t, env.Load())
+	assert.False(t, env.Flag("new_checkout"))
+	assert.Nil(t, env.Flags())
+}
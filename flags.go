@@ -0,0 +1,171 @@
+package dotenv
+
+import (
+	"flag"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// DefineFlagsFromSchema decodes vars into cfg, like [Decode] does, and defines
+// a flag in fs for every env var, declared by cfg, so loaded values become
+// defaults of flags. Parsing of fs sets fields of cfg directly, which gives
+// "flag overrides env overrides file" with one declaration per setting:
+//
+//	env := dotenv.New()
+//	if err := env.Load(); err != nil {
+//		return err
+//	}
+//
+//	cfg := struct {
+//		DatabaseURL string `env:"DATABASE_URL" desc:"Postgres DSN"`
+//		Debug       bool
+//	}{}
+//
+//	err := dotenv.DefineFlagsFromSchema(flag.CommandLine, &cfg,
+//		dotenv.Environ())
+//	if err != nil {
+//		return err
+//	}
+//	flag.Parse()
+//
+// defines flags "-database-url" and "-debug". Name of flag is lower cased key
+// of env var with separators and underscores replaced by "-". Usage of flag is
+// a value of tag "desc" of field. Maps and nil pointers to structs have no
+// flags. If two env vars, like "DB__HOST" and "DB_HOST", or an env var and a
+// flag, already defined in fs, have the same name of flag, it returns an error
+// and fs is left with flags defined before the conflict.
+func DefineFlagsFromSchema(fs *flag.FlagSet, cfg any, vars map[string]string,
+	opts ...DecodeOption,
+) error {
+	if err := Decode(vars, cfg, opts...); err != nil {
+		return err
+	}
+
+	d := decoder{sep: DefaultSeparator}
+	for _, opt := range opts {
+		opt(&d)
+	}
+	return defineFlags(fs, reflect.ValueOf(cfg).Elem(), "", d.sep,
+		make(map[string]string))
+}
+
+// defineFlags defines flags for fields of struct rv. keys contains env vars of
+// flags, defined already, by name of flag.
+func defineFlags(fs *flag.FlagSet, rv reflect.Value, prefix, sep string,
+	keys map[string]string,
+) error {
+	rt := rv.Type()
+	for i := range rt.NumField() {
+		field := rt.Field(i)
+		name, ok := fieldKey(field)
+		if !ok {
+			continue
+		}
+
+		key, fv := prefix+name, rv.Field(i)
+		elem := fv.Type()
+		for elem.Kind() == reflect.Pointer {
+			elem = elem.Elem()
+		}
+
+		switch {
+		case elem.Kind() == reflect.Map:
+			continue
+		case elem.Kind() == reflect.Struct && !isTextValue(elem):
+			if fv.Kind() == reflect.Pointer {
+				if fv.IsNil() {
+					continue
+				}
+				fv = fv.Elem()
+			}
+			if err := defineFlags(fs, fv, key+sep, sep, keys); err != nil {
+				return err
+			}
+			continue
+		}
+
+		fl := flagName(key, sep)
+		if other, ok := keys[fl]; ok {
+			return fmt.Errorf("flag -%v of %v conflicts with %v", fl, key, other)
+		} else if fs.Lookup(fl) != nil {
+			return fmt.Errorf("flag -%v of %v already defined", fl, key)
+		}
+		keys[fl] = key
+
+		usage := field.Tag.Get("desc")
+		if usage == "" {
+			usage = "env " + key
+		}
+		fs.Var(newFlagValue(fv), fl, usage)
+	}
+	return nil
+}
+
+// flagName returns name of flag for key, like "db-host" for "DB__HOST".
+func flagName(key, sep string) string {
+	name := strings.ToLower(strings.ReplaceAll(key, sep, "-"))
+	return strings.ReplaceAll(name, "_", "-")
+}
+
+func newFlagValue(rv reflect.Value) flag.Value {
+	v := flagValue{rv: rv}
+	if t := rv.Type(); t.Kind() == reflect.Bool ||
+		(t.Kind() == reflect.Pointer && t.Elem().Kind() == reflect.Bool) {
+		return &boolFlagValue{v}
+	}
+	return &v
+}
+
+// flagValue implements [flag.Value] for a field of schema.
+type flagValue struct {
+	rv reflect.Value
+}
+
+func (self *flagValue) String() string {
+	// flag package calls it on zero value of the type.
+	if !self.rv.IsValid() {
+		return ""
+	}
+
+	rv := self.rv
+	if rv.Kind() == reflect.Pointer {
+		if rv.IsNil() {
+			return ""
+		}
+		rv = rv.Elem()
+	}
+
+	if rv.Kind() == reflect.Slice && !isTextValue(rv.Type()) {
+		items := make([]string, rv.Len())
+		for i := range rv.Len() {
+			items[i] = docsDefault(rv.Index(i))
+		}
+		return strings.Join(items, ",")
+	}
+	return docsDefault(rv)
+}
+
+func (self *flagValue) Set(s string) error {
+	rv := self.rv
+	if rv.Kind() == reflect.Pointer {
+		if rv.IsNil() {
+			rv.Set(reflect.New(rv.Type().Elem()))
+		}
+		rv = rv.Elem()
+	}
+
+	if rv.Kind() == reflect.Interface && rv.NumMethod() == 0 {
+		rv.Set(reflect.ValueOf(s))
+		return nil
+	}
+	return setValue(rv, s)
+}
+
+// boolFlagValue is a [flagValue], which needs no value in command line, like
+// "-debug".
+type boolFlagValue struct {
+	flagValue
+}
+
+func (self *boolFlagValue) IsBoolFlag() bool { return true }
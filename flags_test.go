@@ -0,0 +1,138 @@
+package dotenv
+
+import (
+	"bytes"
+	"flag"
+	"io"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDefineFlagsFromSchema(t *testing.T) {
+	type dbConfig struct {
+		Host string
+		Port int
+	}
+
+	type config struct {
+		DatabaseURL string `env:"DATABASE_URL" desc:"Postgres DSN"`
+		Debug       bool
+		Timeout     time.Duration
+		Hosts       []string
+		Endpoint    *url.URL
+		Database    dbConfig `env:"DB"`
+		Replica     *dbConfig
+		Labels      map[string]string
+		Skipped     string `env:"-"`
+	}
+
+	vars := map[string]string{
+		"DATABASE_URL": "postgres://localhost",
+		"TIMEOUT":      "1m",
+		"HOSTS":        "a,b",
+		"DB__HOST":     "localhost",
+	}
+
+	tests := []struct {
+		name   string
+		args   []string
+		expect config
+	}{
+		{
+			name: "env only",
+			expect: config{
+				DatabaseURL: "postgres://localhost",
+				Timeout:     time.Minute,
+				Hosts:       []string{"a", "b"},
+				Database:    dbConfig{Host: "localhost"},
+			},
+		},
+		{
+			name: "flags override env",
+			args: []string{
+				"-database-url", "postgres://remote",
+				"-debug",
+				"-timeout", "2s",
+				"-hosts", "c",
+				"-db-host", "remote",
+				"-db-port", "5432",
+			},
+			expect: config{
+				DatabaseURL: "postgres://remote",
+				Debug:       true,
+				Timeout:     2 * time.Second,
+				Hosts:       []string{"c"},
+				Database:    dbConfig{Host: "remote", Port: 5432},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fs := flag.NewFlagSet("test", flag.ContinueOnError)
+			var cfg config
+			require.NoError(t, DefineFlagsFromSchema(fs, &cfg, vars))
+			require.NoError(t, fs.Parse(tt.args))
+			assert.Equal(t, tt.expect, cfg)
+		})
+	}
+}
+
+func TestDefineFlagsFromSchema_usage(t *testing.T) {
+	var cfg struct {
+		DatabaseURL string `env:"DATABASE_URL" desc:"Postgres DSN"`
+		Port        int
+		Endpoint    *url.URL
+		Labels      map[string]string
+	}
+
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	require.NoError(t, DefineFlagsFromSchema(fs, &cfg,
+		map[string]string{"PORT": "8080"}))
+
+	var names []string
+	fs.VisitAll(func(f *flag.Flag) { names = append(names, f.Name) })
+	assert.Equal(t, []string{"database-url", "endpoint", "port"}, names)
+
+	var buf bytes.Buffer
+	fs.SetOutput(&buf)
+	fs.PrintDefaults()
+	assert.Contains(t, buf.String(), "Postgres DSN")
+	assert.Contains(t, buf.String(), "env PORT (default 8080)")
+
+	require.NoError(t, fs.Parse([]string{"-endpoint", "https://example.com"}))
+	require.NotNil(t, cfg.Endpoint)
+	assert.Equal(t, "example.com", cfg.Endpoint.Host)
+}
+
+func TestDefineFlagsFromSchema_errors(t *testing.T) {
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	require.ErrorIs(t, DefineFlagsFromSchema(fs, struct{}{}, nil),
+		ErrNotStructPtr)
+
+	var cfg struct{ Port int }
+	require.Error(t, DefineFlagsFromSchema(fs, &cfg,
+		map[string]string{"PORT": "abc"}))
+
+	fs.SetOutput(io.Discard)
+	require.NoError(t, DefineFlagsFromSchema(fs, &cfg, nil))
+	require.Error(t, fs.Parse([]string{"-port", "abc"}))
+	require.ErrorContains(t, DefineFlagsFromSchema(fs, &cfg, nil),
+		"flag -port of PORT already defined")
+}
+
+func TestDefineFlagsFromSchema_conflict(t *testing.T) {
+	type dbConfig struct{ Host string }
+	var cfg struct {
+		DB     dbConfig
+		DBHost string `env:"DB_HOST"`
+	}
+
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	require.ErrorContains(t, DefineFlagsFromSchema(fs, &cfg, nil),
+		"flag -db-host of DB_HOST conflicts with DB__HOST")
+}
@@ -0,0 +1,66 @@
+package dotenv
+
+import (
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// WithFS configures [Loader] to run the whole lookup and load pipeline against
+// fsys instead of the OS filesystem: checking existence of files, walking up
+// parent dirs and reading .env files. So .env files can be loaded from
+// [embed.FS], [testing/fstest.MapFS] or read-only deployment bundles:
+//
+//	//go:embed config
+//	var configFS embed.FS
+//
+//	scoped, err := dotenv.New().WithFS(configFS).For("/config/prod")
+//
+// Paths of fsys are absolute paths for [Loader], with root of fsys as "/", so
+// lookup starts at "/" or at dir of [Loader.For], and [Result] contains paths
+// like "/config/prod/.env". Secret files of [Loader.WithSecretsEnabled] aren't
+// checked for permissions and git status, because files of fsys have no
+// meaningful ones.
+func (self *Loader) WithFS(fsys fs.FS) *Loader {
+	self.fsys, self.filer = fsys, fsFiler{fsys: fsys}
+	return self
+}
+
+// fsFiler implements [Filer] using [fs.FS].
+type fsFiler struct {
+	fsys fs.FS
+}
+
+func (self fsFiler) Stat(name string) (os.FileInfo, error) {
+	return fs.Stat(self.fsys, fsName(name)) //nolint:wrapcheck // return it as is
+}
+
+// fsName converts absolute path name into a name of [fs.FS], like "/a/b" into
+// "a/b" and "/" into ".".
+func fsName(name string) string {
+	name = strings.TrimPrefix(path.Clean(filepath.ToSlash(name)), "/")
+	if name == "" {
+		return "."
+	}
+	return name
+}
+
+// readFileBytes reads file fname from configured [fs.FS] or from the OS
+// filesystem.
+func (self *Loader) readFileBytes(fname string) ([]byte, error) {
+	if self.fsys != nil {
+		return fs.ReadFile(self.fsys, fsName(fname)) //nolint:wrapcheck // as is
+	}
+	return os.ReadFile(fname) //nolint:wrapcheck // return it as is
+}
+
+// openFile opens file fname from fsys or from the OS filesystem, if fsys is
+// nil.
+func openFile(fsys fs.FS, fname string) (fs.File, error) {
+	if fsys != nil {
+		return fsys.Open(fsName(fname)) //nolint:wrapcheck // return it as is
+	}
+	return os.Open(fname) //nolint:wrapcheck // return it as is
+}
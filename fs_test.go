@@ -0,0 +1,65 @@
+package dotenv
+
+import (
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestFS() fstest.MapFS {
+	return fstest.MapFS{
+		"go.mod":             {Data: []byte("module test\n")},
+		".env":               {Data: []byte("TEST_VAR1=root\nTEST_VAR2=root\n")},
+		"app/.env":           {Data: []byte("TEST_VAR1=app\n")},
+		"app/.env.local":     {Data: []byte("TEST_VAR2=app local\n")},
+		"app/sub/dir/README": {Data: []byte("readme\n")},
+	}
+}
+
+func TestLoader_WithFS(t *testing.T) {
+	restoreEnvVars(t)
+
+	env := New().WithoutSetenv()
+	assert.Same(t, env, env.WithFS(newTestFS()))
+	require.NoError(t, env.Load())
+	assert.Equal(t, "root", env.Getenv(allEnvVars[0]))
+	assert.Equal(t, Stop{Reason: StopFound, Dir: "/"}, env.Result().Stop)
+
+	b, err := env.Result().ProvenanceJSON()
+	require.NoError(t, err)
+	assert.Contains(t, string(b), `"line":2`)
+}
+
+func TestLoader_WithFS_For(t *testing.T) {
+	restoreEnvVars(t)
+
+	env := New().WithFS(newTestFS()).WithDepth(3)
+	scoped, err := env.For("app/sub/dir")
+	require.NoError(t, err)
+	assert.Equal(t, "/app/sub/dir", scoped.Dir)
+	assert.Equal(t, "app", scoped.Getenv(allEnvVars[0]))
+	assert.Equal(t, "app local", scoped.Getenv(allEnvVars[1]))
+	assert.Equal(t, Stop{Reason: StopFound, Dir: "/app"}, scoped.Result().Stop)
+
+	scoped, err = env.WithDepth(1).For("/app/sub/dir")
+	require.NoError(t, err)
+	assert.Empty(t, scoped.Getenv(allEnvVars[0]))
+}
+
+func TestLoader_WithFS_readError(t *testing.T) {
+	restoreEnvVars(t)
+
+	fsys := newTestFS()
+	fsys[".env"] = &fstest.MapFile{Data: []byte("TEST_VAR1='unterminated\n")}
+	env := New().WithoutSetenv().WithFS(fsys)
+	require.Error(t, env.Load())
+}
+
+func TestFsName(t *testing.T) {
+	assert.Equal(t, ".", fsName("/"))
+	assert.Equal(t, ".", fsName(""))
+	assert.Equal(t, "a/b", fsName("/a/b/"))
+	assert.Equal(t, "a", fsName("/../a"))
+}
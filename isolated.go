@@ -0,0 +1,95 @@
+package dotenv
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"os/exec"
+	"slices"
+)
+
+// Env is a read-only view of environment, built by [Loader.RunIsolated]. It
+// never reads or changes the process environment.
+type Env struct {
+	vars map[string]string
+}
+
+// Getenv returns value of env var named key, like [os.Getenv] does.
+func (self Env) Getenv(key string) string { return self.vars[key] }
+
+// LookupEnv returns value of env var named key and true, if it exists, like
+// [os.LookupEnv] does.
+func (self Env) LookupEnv(key string) (string, bool) {
+	v, ok := self.vars[key]
+	return v, ok
+}
+
+// Environ returns a copy of env vars in the form "key=value", like
+// [os.Environ] does, sorted by key. It's suitable for [exec.Cmd.Env].
+func (self Env) Environ() []string {
+	environ := make([]string, 0, len(self.vars))
+	for _, k := range slices.Sorted(maps.Keys(self.vars)) {
+		environ = append(environ, k+"="+self.vars[k])
+	}
+	return environ
+}
+
+// Map returns a copy of env vars as a map, suitable for [Decode].
+func (self Env) Map() map[string]string { return maps.Clone(self.vars) }
+
+// Command returns [exec.Cmd] like [exec.CommandContext] does, with this
+// environment as its env.
+func (self Env) Command(ctx context.Context, name string, arg ...string,
+) *exec.Cmd {
+	cmd := exec.CommandContext(ctx, name, arg...)
+	cmd.Env = self.Environ()
+	return cmd
+}
+
+// RunIsolated loads .env files like [Loader.Load] does, but never calls
+// [os.Setenv]. Instead it calls fn with a view of merged environment, see
+// [Loader.LookupEnv], and returns its error. So libraries can use [Loader]
+// without global mutation of the process environment:
+//
+//	err := dotenv.New().RunIsolated(ctx, func(env dotenv.Env) error {
+//		return env.Command(ctx, "make", "test").Run()
+//	})
+//
+// It returns ctx.Err() if ctx is done before fn is called.
+func (self *Loader) RunIsolated(ctx context.Context, fn func(env Env) error,
+) error {
+	if err := ctx.Err(); err != nil {
+		return fmt.Errorf("run isolated: %w", err)
+	}
+
+	noSetenv := self.noSetenv
+	self.noSetenv = true
+	defer func() { self.noSetenv = noSetenv }()
+
+	if err := self.Load(); err != nil {
+		return err
+	} else if err := ctx.Err(); err != nil {
+		return fmt.Errorf("run isolated: %w", err)
+	}
+	return fn(Env{vars: self.environ()})
+}
+
+// Read loads .env files like [Loader.Load] does, with the same lookup and
+// precedence rules, but never calls [os.Setenv]. Instead it returns effective
+// values of all env vars, defined by .env files, as a map. So values can be
+// fed into another config system without mutation of the process environment:
+//
+//	vars, err := dotenv.New().Read()
+//
+// Env vars, which are already defined by the process environment, keep their
+// values, like they do with Load.
+func (self *Loader) Read() (map[string]string, error) {
+	noSetenv := self.noSetenv
+	self.noSetenv = true
+	defer func() { self.noSetenv = noSetenv }()
+
+	if err := self.Load(); err != nil {
+		return nil, err
+	}
+	return maps.Clone(self.loaded), nil
+}
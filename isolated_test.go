@@ -0,0 +1,99 @@
+package dotenv
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoader_RunIsolated(t *testing.T) {
+	changeDir(t, "testdata")
+	restoreEnvVars(t)
+	t.Setenv(allEnvVars[1], "from env")
+
+	env := New()
+	env.setenvFn = func(key, value string) error {
+		t.Fatalf("unexpected setenv %v=%v", key, value)
+		return nil
+	}
+
+	errTest := errors.New("test error")
+	err := env.RunIsolated(context.Background(), func(env Env) error {
+		assert.Equal(t, "testdata", env.Getenv(allEnvVars[0]))
+		assert.Equal(t, "from env", env.Getenv(allEnvVars[1]))
+		_, ok := env.LookupEnv("TEST_VAR_NOT_EXISTS")
+		assert.False(t, ok)
+
+		environ := env.Environ()
+		assert.Contains(t, environ, allEnvVars[0]+"=testdata")
+		assert.IsNonDecreasing(t, environ)
+		assert.Equal(t, "testdata", env.Map()[allEnvVars[0]])
+
+		cmd := env.Command(context.Background(), "true")
+		assert.Equal(t, environ, cmd.Env)
+		return errTest
+	})
+	require.ErrorIs(t, err, errTest)
+	assert.False(t, env.noSetenv)
+
+	_, ok := os.LookupEnv(allEnvVars[0])
+	assert.False(t, ok)
+}
+
+func TestLoader_RunIsolated_canceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	err := New().RunIsolated(ctx, func(env Env) error {
+		t.Fatal("unexpected call")
+		return nil
+	})
+	require.ErrorIs(t, err, context.Canceled)
+}
+
+func TestLoader_Read(t *testing.T) {
+	changeDir(t, "testdata/g")
+	restoreEnvVars(t)
+	t.Setenv(allEnvVars[1], "env")
+
+	env := New()
+	vars, err := env.Read()
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{
+		allEnvVars[0]: "local",
+		allEnvVars[1]: "env",
+	}, vars)
+	assert.Empty(t, os.Getenv(allEnvVars[0]))
+	assert.False(t, env.noSetenv)
+
+	vars[allEnvVars[0]] = "changed"
+	assert.Equal(t, "local", env.loaded[allEnvVars[0]])
+
+	_, err = New().WithEnvSuffix("a/b").Read()
+	require.ErrorIs(t, err, ErrInvalidConfig)
+}
+
+func TestLoader_Read_thenLoad(t *testing.T) {
+	dir := t.TempDir()
+	fname := filepath.Join(dir, ".env")
+	require.NoError(t, os.WriteFile(fname, []byte("TEST_VAR1=read\n"), 0o600))
+	changeDir(t, dir)
+	restoreEnvVars(t)
+
+	env := New().WithRootDir(".")
+	vars, err := env.Read()
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{allEnvVars[0]: "read"}, vars)
+
+	require.NoError(t, os.WriteFile(fname, []byte("TEST_VAR1=load\n"), 0o600))
+	require.NoError(t, env.Load())
+	assert.Equal(t, "load", os.Getenv(allEnvVars[0]))
+	assert.Empty(t, env.vars)
+	assert.Empty(t, env.Result().ExistingKeys)
+	assert.Equal(t, []string{allEnvVars[0]}, env.Result().SetKeys)
+}
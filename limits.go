@@ -0,0 +1,87 @@
+package dotenv
+
+import (
+	"errors"
+	"maps"
+	"path"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// ErrInvalidValue is wrapped by every [ValueError].
+var ErrInvalidValue = errors.New("invalid value")
+
+// ValueError returned by [Loader.Load] if a value of .env file violates limits,
+// configured by [Loader.WithMaxValueLen] or [Loader.WithSingleLineKeys]. Use
+// [errors.As] to get it.
+type ValueError struct {
+	// Key is a name of env var.
+	Key string
+
+	// File is a name of .env file, which defines Key.
+	File string
+
+	// Reason describes violated limit.
+	Reason string
+}
+
+func (self *ValueError) Error() string {
+	return "invalid value of " + self.Key + " from " + self.File + ": " +
+		self.Reason
+}
+
+// Unwrap returns [ErrInvalidValue].
+func (self *ValueError) Unwrap() error { return ErrInvalidValue }
+
+// WithMaxValueLen configures [Loader.Load] to return an error wrapping
+// [ValueError], if any value of .env files is longer than n bytes. It protects
+// downstream parsers from pathological inputs. Zero or negative n disables the
+// limit.
+func (self *Loader) WithMaxValueLen(n int) *Loader {
+	self.maxValueLen = n
+	return self
+}
+
+// WithSingleLineKeys configures [Loader.Load] to return an error wrapping
+// [ValueError], if value of any key, matching any of patterns, contains a new
+// line. Patterns use syntax of [path.Match], like "*_URL" or "*" for all keys.
+func (self *Loader) WithSingleLineKeys(patterns ...string) *Loader {
+	self.singleLine = patterns
+	return self
+}
+
+// checkValues returns an error if any of vars, loaded from fname, violates
+// configured limits.
+func (self *Loader) checkValues(fname string, vars map[string]string) error {
+	if self.maxValueLen <= 0 && len(self.singleLine) == 0 {
+		return nil
+	}
+
+	var errs []error
+	for _, key := range slices.Sorted(maps.Keys(vars)) {
+		v := vars[key]
+		if self.maxValueLen > 0 && len(v) > self.maxValueLen {
+			errs = append(errs, &ValueError{
+				Key:  key,
+				File: fname,
+				Reason: "length " + strconv.Itoa(len(v)) + " exceeds " +
+					strconv.Itoa(self.maxValueLen),
+			})
+		}
+		if strings.ContainsAny(v, "\r\n") && self.isSingleLine(key) {
+			errs = append(errs, &ValueError{
+				Key: key, File: fname, Reason: "contains new line",
+			})
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// isSingleLine returns true if key matches any of single line patterns.
+func (self *Loader) isSingleLine(key string) bool {
+	return slices.ContainsFunc(self.singleLine, func(pattern string) bool {
+		ok, _ := path.Match(pattern, key)
+		return ok
+	})
+}
@@ -0,0 +1,52 @@
+package dotenv
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoader_checkValues(t *testing.T) {
+	vars := map[string]string{
+		"CERT": "line1\nline2",
+		"LONG": "0123456789",
+		"URL":  "https://example.com\r\n",
+	}
+
+	env := New()
+	require.NoError(t, env.checkValues(".env", vars))
+
+	assert.Same(t, env, env.WithMaxValueLen(21))
+	require.NoError(t, env.checkValues(".env", vars))
+
+	assert.Same(t, env, env.WithMaxValueLen(5).WithSingleLineKeys("*_URL", "URL"))
+	err := env.checkValues(".env", vars)
+	require.ErrorIs(t, err, ErrInvalidValue)
+
+	var valueErr *ValueError
+	require.True(t, errors.As(err, &valueErr))
+	assert.Equal(t, ValueError{
+		Key: "CERT", File: ".env", Reason: "length 11 exceeds 5",
+	}, *valueErr)
+	assert.Equal(t, "invalid value of CERT from .env: length 11 exceeds 5\n"+
+		"invalid value of LONG from .env: length 10 exceeds 5\n"+
+		"invalid value of URL from .env: length 21 exceeds 5\n"+
+		"invalid value of URL from .env: contains new line", err.Error())
+
+	env.WithMaxValueLen(0).WithSingleLineKeys("*")
+	err = env.checkValues(".env", vars)
+	require.ErrorIs(t, err, ErrInvalidValue)
+	assert.Equal(t, "invalid value of CERT from .env: contains new line\n"+
+		"invalid value of URL from .env: contains new line", err.Error())
+}
+
+func TestLoader_WithMaxValueLen(t *testing.T) {
+	changeDir(t, "testdata/g")
+	restoreEnvVars(t)
+
+	err := New().WithMaxValueLen(4).Load()
+	require.ErrorIs(t, err, ErrInvalidValue)
+	assert.ErrorContains(t, err, "TEST_VAR1 from .env.local")
+}
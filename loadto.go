@@ -0,0 +1,36 @@
+package dotenv
+
+// LoadTo loads .env files like [Loader.Load] does and decodes merged
+// environment, see [Loader.LookupEnv], into a struct, pointed by v, like
+// [Decode] does with opts:
+//
+//	cfg := struct {
+//		SomeOpt string `env:"ENV_VAR1"`
+//	}{
+//		SomeOpt: "some default value, because we don't have .env file(s)",
+//	}
+//
+//	if err := dotenv.New().LoadTo(&cfg); err != nil {
+//		log.Fatalf("error loading .env files: %v", err)
+//	}
+//
+// Fields without env vars keep their current values, so they are defaults.
+func (self *Loader) LoadTo(v any, opts ...DecodeOption) error {
+	return self.Load(func() error { return Decode(self.environ(), v, opts...) })
+}
+
+// LoadInto loads .env files using [Loader], created with opts, and returns a
+// struct of type T, decoded from merged environment, see [Loader.LoadTo]. So
+// one call returns typed config:
+//
+//	type config struct {
+//		DatabaseURL string `env:"DATABASE_URL"`
+//		Port        int
+//	}
+//
+//	cfg, err := dotenv.LoadInto[config]()
+func LoadInto[T any](opts ...Option) (T, error) {
+	var v T
+	err := New(opts...).LoadTo(&v)
+	return v, err
+}
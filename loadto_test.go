@@ -0,0 +1,42 @@
+package dotenv
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoader_LoadTo(t *testing.T) {
+	changeDir(t, "testdata/g")
+	restoreEnvVars(t)
+
+	cfg := struct {
+		Var1  string `env:"TEST_VAR1"`
+		Var2  string `env:"TEST_VAR2"`
+		Other string `env:"TEST_OTHER"`
+	}{Other: "default"}
+
+	env := New().WithoutSetenv()
+	require.NoError(t, env.LoadTo(&cfg))
+	assert.Equal(t, "local", cfg.Var1)
+	assert.Equal(t, "second", cfg.Var2)
+	assert.Equal(t, "default", cfg.Other)
+
+	require.ErrorIs(t, env.LoadTo(cfg), ErrNotStructPtr)
+}
+
+func TestLoadInto(t *testing.T) {
+	changeDir(t, "testdata/g")
+	restoreEnvVars(t)
+
+	type flat struct {
+		Var1 string `env:"TEST_VAR1"`
+	}
+	got, err := LoadInto[flat]()
+	require.NoError(t, err)
+	assert.Equal(t, flat{Var1: "local"}, got)
+
+	_, err = LoadInto[string]()
+	require.ErrorIs(t, err, ErrNotStructPtr)
+}
@@ -0,0 +1,54 @@
+package dotenv
+
+import (
+	"cmp"
+	"context"
+	"log/slog"
+)
+
+// WithLogger configures [Loader.Load] to emit debug records of lookup decisions
+// into logger: every visited dir, every stat of a candidate file, why lookup
+// stopped and every loaded source. So "why didn't my .env load" can be
+// diagnosed without reading the source:
+//
+//	env := dotenv.New().WithLogger(slog.Default().With("component", "dotenv"))
+//
+// Records are emitted only, if logger is enabled for [slog.LevelDebug]. Values
+// of env vars are never logged. nil logger disables logging.
+func (self *Loader) WithLogger(logger *slog.Logger) *Loader {
+	self.logger = logger
+	return self
+}
+
+// logEnabled returns true if debug records must be emitted.
+func (self *Loader) logEnabled() bool {
+	return self.logger != nil &&
+		self.logger.Enabled(self.logContext(), slog.LevelDebug)
+}
+
+func (self *Loader) logContext() context.Context {
+	return cmp.Or[context.Context](self.ctx, context.Background())
+}
+
+// logDebug emits a debug record with msg and attrs, if logging is enabled.
+func (self *Loader) logDebug(msg string, attrs ...slog.Attr) {
+	if self.logEnabled() {
+		self.logger.LogAttrs(self.logContext(), slog.LevelDebug, msg, attrs...)
+	}
+}
+
+// logStat emits a debug record about stat of file fname.
+func (self *Loader) logStat(fname string, exists bool, err error) {
+	if !self.logEnabled() {
+		return
+	}
+
+	attrs := []slog.Attr{
+		slog.String("file", fname),
+		slog.Bool("exists", exists),
+	}
+	if err != nil {
+		attrs = append(attrs, slog.String("error", err.Error()))
+	}
+	self.logDebug("stat file", attrs...)
+}
@@ -0,0 +1,59 @@
+package dotenv
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoader_WithLogger(t *testing.T) {
+	changeDir(t, filepath.Join("testdata", "g"))
+	restoreEnvVars(t)
+
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewJSONHandler(&buf,
+		&slog.HandlerOptions{Level: slog.LevelDebug}))
+	require.NoError(t, New().WithLogger(logger).Load())
+
+	var records []map[string]any
+	dec := json.NewDecoder(&buf)
+	for dec.More() {
+		var rec map[string]any
+		require.NoError(t, dec.Decode(&rec))
+		delete(rec, "time")
+		records = append(records, rec)
+	}
+
+	assert.Contains(t, records,
+		map[string]any{"level": "DEBUG", "msg": "visit dir", "dir": "."})
+	assert.Contains(t, records, map[string]any{
+		"level": "DEBUG", "msg": "stat file", "file": ".env", "exists": true,
+	})
+	assert.Contains(t, records, map[string]any{
+		"level": "DEBUG", "msg": "stat file", "file": ".dotenvignore",
+		"exists": false,
+	})
+	assert.Contains(t, records, map[string]any{
+		"level": "DEBUG", "msg": "stop lookup", "dir": ".", "reason": "found",
+	})
+	assert.Contains(t, records, map[string]any{
+		"level": "DEBUG", "msg": "load source", "file": ".env", "kind": "file",
+	})
+}
+
+func TestLoader_WithLogger_disabled(t *testing.T) {
+	changeDir(t, filepath.Join("testdata", "g"))
+	restoreEnvVars(t)
+
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewJSONHandler(&buf, nil))
+	require.NoError(t, New().WithLogger(logger).Load())
+	assert.Empty(t, buf.String())
+
+	require.NoError(t, New().WithLogger(nil).Load())
+}
@@ -0,0 +1,65 @@
+package dotenv
+
+import (
+	"context"
+	"fmt"
+)
+
+// LookupError is returned by [Loader.LoadContext], if its context was done
+// during lookup of .env files. It wraps error of the context, like
+// [context.DeadlineExceeded].
+type LookupError struct {
+	// Path is the last path, touched by lookup.
+	Path string
+
+	// Err is error of the context.
+	Err error
+}
+
+func (self *LookupError) Error() string {
+	return fmt.Sprintf("lookup interrupted at %v: %v", self.Path, self.Err)
+}
+
+func (self *LookupError) Unwrap() error { return self.Err }
+
+// LoadContext loads .env files like [Loader.Load] does, but checks ctx between
+// dirs and candidate files of lookup. So pathological setups, like thousands of
+// candidates on a dead network mount, fail fast with [LookupError], which
+// wraps ctx.Err():
+//
+//	ctx, cancel := context.WithTimeout(ctx, time.Second)
+//	defer cancel()
+//	err := env.LoadContext(ctx)
+//	if errors.Is(err, context.DeadlineExceeded) {
+//		var lookupErr *dotenv.LookupError
+//		errors.As(err, &lookupErr)
+//		log.Printf("lookup stuck at %v", lookupErr.Path)
+//	}
+//
+// A single [Filer.Stat] call can't be interrupted.
+func (self *Loader) LoadContext(ctx context.Context,
+	callbacks ...func() error,
+) error {
+	self.ctx = ctx
+	defer func() { self.ctx = nil }()
+	return self.Load(callbacks...)
+}
+
+// checkContext returns [LookupError], if context of [Loader.LoadContext] is
+// done.
+func (self *Loader) checkContext() error {
+	if self.ctx == nil {
+		return nil
+	} else if err := self.ctx.Err(); err != nil {
+		return &LookupError{Path: self.lastPath, Err: err}
+	}
+	return nil
+}
+
+// touchPath remembers path as the last path, touched by lookup, if it's
+// needed by [Loader.LoadContext].
+func (self *Loader) touchPath(path string) {
+	if self.ctx != nil {
+		self.lastPath = path
+	}
+}
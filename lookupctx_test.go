@@ -0,0 +1,63 @@
+package dotenv
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dsh2dsh/expx-dotenv/internal/mocks"
+)
+
+func TestLoader_LoadContext(t *testing.T) {
+	restoreEnvVars(t)
+	changeDir(t, filepath.Join("testdata", "g"))
+
+	env := New()
+	require.NoError(t, env.LoadContext(context.Background()))
+	assert.Equal(t, "local", os.Getenv("TEST_VAR1"))
+}
+
+func TestLoader_LoadContext_canceled(t *testing.T) {
+	restoreEnvVars(t)
+	changeDir(t, filepath.Join("testdata", "g"))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	err := New().LoadContext(ctx)
+	require.ErrorIs(t, err, context.Canceled)
+	var lookupErr *LookupError
+	require.ErrorAs(t, err, &lookupErr)
+	assert.Equal(t, ".", lookupErr.Path)
+	assert.Empty(t, os.Getenv("TEST_VAR1"))
+}
+
+func TestLoader_LoadContext_betweenFiles(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var touched []string
+	filer := mocks.NewMockFiler(t)
+	filer.EXPECT().Stat(mock.Anything).RunAndReturn(
+		func(name string) (os.FileInfo, error) {
+			touched = append(touched, name)
+			if len(touched) == 2 {
+				cancel()
+			}
+			return nil, os.ErrNotExist
+		})
+	env := New(WithFiler(filer))
+
+	err := env.LoadContext(ctx)
+	require.ErrorIs(t, err, context.Canceled)
+	var lookupErr *LookupError
+	require.ErrorAs(t, err, &lookupErr)
+	assert.Len(t, touched, 2)
+	assert.Equal(t, touched[1], lookupErr.Path)
+	assert.Nil(t, env.ctx)
+}
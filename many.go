@@ -0,0 +1,63 @@
+package dotenv
+
+import (
+	"errors"
+	"fmt"
+	"os"
+)
+
+// LoadMany loads .env files for every project dir of dirs, like [Loader.For]
+// does, and returns views of their environments by dir. All of them share
+// results of [Filer.Stat] calls, so overlapping parent dirs are checked once.
+// It's targeted at monorepo build orchestrators, which need environments of
+// dozens of packages at once:
+//
+//	envs, err := dotenv.LoadMany([]string{"services/api", "services/web"},
+//		dotenv.WithEnvSuffix("ci"))
+//	if err != nil {
+//		return err
+//	}
+//	for dir, scoped := range envs {
+//		fmt.Println(dir, scoped.Result().Files, scoped.Getenv("DATABASE_URL"))
+//	}
+//
+// opts configure [Loader], see [New]. It never calls [os.Setenv]. Files must
+// not change during the call, because results of stat are cached. If loading
+// of any dir failed, it continues with other dirs and returns all errors
+// joined, together with views of succeeded dirs.
+func LoadMany(dirs []string, opts ...Option) (map[string]*ScopedEnv, error) {
+	l := New(opts...)
+	l.filer = &cachedFiler{filer: l.filer, stats: make(map[string]cachedStat)}
+
+	envs := make(map[string]*ScopedEnv, len(dirs))
+	var errs []error
+	for _, dir := range dirs {
+		scoped, err := l.For(dir)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("load %v: %w", dir, err))
+			continue
+		}
+		envs[dir] = scoped
+	}
+	return envs, errors.Join(errs...)
+}
+
+// cachedFiler implements [Filer], which caches results of Stat calls of filer.
+type cachedFiler struct {
+	filer Filer
+	stats map[string]cachedStat
+}
+
+type cachedStat struct {
+	fi  os.FileInfo
+	err error
+}
+
+func (self *cachedFiler) Stat(name string) (os.FileInfo, error) {
+	if c, ok := self.stats[name]; ok {
+		return c.fi, c.err
+	}
+	fi, err := self.filer.Stat(name)
+	self.stats[name] = cachedStat{fi: fi, err: err}
+	return fi, err //nolint:wrapcheck // return it as is
+}
@@ -0,0 +1,51 @@
+package dotenv
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type countingFiler struct {
+	calls map[string]int
+}
+
+func (self *countingFiler) Stat(name string) (os.FileInfo, error) {
+	self.calls[name]++
+	return os.Stat(name) //nolint:wrapcheck // return it as is
+}
+
+func TestLoadMany(t *testing.T) {
+	restoreEnvVars(t)
+	testdata := valueNoError[string](t)(filepath.Abs("testdata"))
+
+	filer := &countingFiler{calls: make(map[string]int)}
+	envs, err := LoadMany([]string{"testdata/a", "testdata/e/f", "testdata/g"},
+		WithFiler(filer))
+	require.NoError(t, err)
+	require.Len(t, envs, 3)
+
+	assert.Equal(t, "testdata", envs["testdata/a"].Getenv(allEnvVars[0]))
+	assert.Equal(t, testdata, envs["testdata/a"].Result().Dir)
+	assert.Equal(t, "testdata", envs["testdata/e/f"].Getenv(allEnvVars[0]))
+	assert.Equal(t, "local", envs["testdata/g"].Getenv(allEnvVars[0]))
+	assert.Empty(t, os.Getenv(allEnvVars[0]))
+
+	assert.Contains(t, filer.calls, filepath.Join(testdata, ".env"))
+	for name, n := range filer.calls {
+		assert.Equal(t, 1, n, "%v checked %v times", name, n)
+	}
+}
+
+func TestLoadMany_error(t *testing.T) {
+	restoreEnvVars(t)
+
+	envs, err := LoadMany([]string{"testdata/d", "testdata/g"},
+		WithEnvSuffix("error"))
+	require.ErrorContains(t, err, "load testdata/d")
+	assert.NotContains(t, envs, "testdata/d")
+	assert.Contains(t, envs, "testdata/g")
+}
@@ -0,0 +1,36 @@
+package dotenv
+
+// LoadFunc is a step of [Loader.Load], wrapped by a [Middleware]. The innermost
+// LoadFunc is the load pipeline itself: lookup of .env files, loading of them
+// and calling of callbacks.
+type LoadFunc func() error
+
+// Middleware wraps next step of [Loader.Load] and returns a new step. It can do
+// something before and after calling of next, call it more than once or not
+// call it at all. For instance, a middleware, which logs duration of every
+// Load:
+//
+//	timing := func(next dotenv.LoadFunc) dotenv.LoadFunc {
+//		return func() error {
+//			t0 := time.Now()
+//			err := next()
+//			log.Printf("loaded in %v: %v", time.Since(t0), err)
+//			return err
+//		}
+//	}
+//
+//	err := dotenv.New().WithMiddleware(timing).Load()
+//
+// Every call of next starts the load pipeline from scratch, so [Loader.Result]
+// always describes the last one.
+type Middleware func(next LoadFunc) LoadFunc
+
+// WithMiddleware configures [Loader] to wrap every [Loader.Load] by mws.
+// Middlewares are composed in defined order: the first one of all configured
+// middlewares is the outermost, so it's called first and its next is the
+// second one, and so on. Every call appends mws to already configured
+// middlewares.
+func (self *Loader) WithMiddleware(mws ...Middleware) *Loader {
+	self.middlewares = append(self.middlewares, mws...)
+	return self
+}
@@ -0,0 +1,83 @@
+package dotenv
+
+import (
+	"errors"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoader_WithMiddleware(t *testing.T) {
+	changeDir(t, "testdata/g")
+	restoreEnvVars(t)
+
+	var calls []string
+	named := func(name string) Middleware {
+		return func(next LoadFunc) LoadFunc {
+			return func() error {
+				calls = append(calls, name+" before")
+				err := next()
+				calls = append(calls, name+" after")
+				return err
+			}
+		}
+	}
+
+	env := New()
+	assert.Same(t, env, env.WithMiddleware(named("a"), named("b")))
+	assert.Same(t, env, env.WithMiddleware(named("c")))
+
+	require.NoError(t, env.Load(func() error {
+		calls = append(calls, "callback")
+		return nil
+	}))
+	assert.Equal(t, []string{
+		"a before", "b before", "c before",
+		"callback",
+		"c after", "b after", "a after",
+	}, calls)
+	assert.Equal(t, "local", os.Getenv(allEnvVars[0]))
+}
+
+func TestLoader_WithMiddleware_retry(t *testing.T) {
+	changeDir(t, "testdata/g")
+	restoreEnvVars(t)
+
+	retry := func(next LoadFunc) LoadFunc {
+		return func() (err error) {
+			for range 3 {
+				if err = next(); err == nil {
+					break
+				}
+			}
+			return
+		}
+	}
+
+	var attempts int
+	env := New().WithMiddleware(retry)
+	require.NoError(t, env.Load(func() error {
+		if attempts++; attempts < 3 {
+			return errors.New("not yet")
+		}
+		return nil
+	}))
+	assert.Equal(t, 3, attempts)
+	require.NotNil(t, env.Result())
+	assert.Len(t, env.Result().Stats.Files, 2)
+}
+
+func TestLoader_WithMiddleware_skip(t *testing.T) {
+	changeDir(t, "testdata/g")
+	restoreEnvVars(t)
+
+	cached := errors.New("cached")
+	env := New().WithMiddleware(func(next LoadFunc) LoadFunc {
+		return func() error { return cached }
+	})
+	require.ErrorIs(t, env.Load(), cached)
+	assert.Nil(t, env.Result())
+	assert.Empty(t, os.Getenv(allEnvVars[0]))
+}
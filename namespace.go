@@ -0,0 +1,75 @@
+package dotenv
+
+import (
+	"maps"
+	"slices"
+)
+
+// Namespace returns a new [Loader], which loads .env files of library name into
+// a private namespace. It looks for .env files the same way, as this [Loader]
+// is configured to, but with name of library in their names:
+//
+//  1. .env.{NAME}.{ENVIRONMENT_NAME}.local
+//  2. .env.{NAME}.local
+//  3. .env.{NAME}.{ENVIRONMENT_NAME}
+//  4. .env.{NAME}
+//
+// Returned [Loader] is hermetic, see [Loader.WithHermetic], so it never
+// touches environment of host application and isn't affected by it. Read env
+// vars of the namespace using its [Loader.Getenv], [Loader.LookupEnv],
+// [Loader.LoadTo] or [Loader.Read]. For instance, a SDK can ship .env files
+// support without conflicting with own [Loader] of application:
+//
+//	ns := dotenv.New().Namespace("mylib")
+//	if err := ns.Load(); err != nil {
+//		return err
+//	}
+//	apiKey := ns.Getenv("API_KEY")
+//
+// Configuration is copied, like [Loader.For] does, so later changes of this
+// [Loader] don't change returned one. Settings, which belong to .env files or
+// env vars of application, aren't copied: [Loader.WithFiles],
+// [Loader.WithFileOrder], [Loader.WithRequired], [Loader.WithRequiredKeys],
+// [Loader.WithRequiredFiles], [Loader.WithDisallowUnknown], [Loader.WithValues],
+// [Loader.WithSnapshotFallback] and [Loader.WithFeatureFlags].
+func (self *Loader) Namespace(name string) *Loader {
+	ns := *self
+	ns.resetState()
+	ns.rootFiles = slices.Clone(self.rootFiles)
+	ns.middlewares = slices.Clip(self.middlewares)
+	ns.priorities = maps.Clone(self.priorities)
+
+	ns.files, ns.fileOrder = nil, nil
+	ns.required, ns.requiredFiles, ns.mustFind = nil, nil, false
+	ns.schema, ns.schemaErr = nil, nil
+	ns.values, ns.snapshotFallback, ns.featureFlags = nil, "", false
+
+	ns.namespace, ns.namespaced = name, true
+	ns.envFilesFn = ns.namespaceFiles
+	return ns.WithHermetic()
+}
+
+// resetState resets state of all previous loads, keeping configuration only.
+func (self *Loader) resetState() {
+	self.stats, self.stop, self.warnings = Stats{}, Stop{}, nil
+	self.aead, self.loaded, self.sources, self.winners = nil, nil, nil, nil
+	self.precedence, self.provenance = nil, nil
+	self.skipped, self.envDir, self.flags = nil, "", nil
+	self.lastPath, self.dirCache = "", nil
+	self.vars, self.pending, self.lastDir = nil, nil, ""
+	self.result, self.recording, self.ctx = nil, nil, nil
+}
+
+// namespaceFiles returns list of .env files of configured namespace for
+// envName.
+func (self *Loader) namespaceFiles(envName string) []string {
+	prefix := self.baseName() + "." + self.namespace
+	if envName == "" {
+		return []string{prefix + ".local", prefix}
+	}
+
+	return []string{
+		prefix + "." + envName + ".local", prefix + ".local",
+		prefix + "." + envName, prefix,
+	}
+}
@@ -0,0 +1,187 @@
+package dotenv
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"errors"
+	"os"
+	"reflect"
+	"testing"
+	"testing/fstest"
+	"unsafe"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoader_Namespace(t *testing.T) {
+	changeDir(t, "testdata/j/k")
+	restoreEnvVars(t)
+	t.Setenv(allEnvVars[1], "host")
+
+	app := New().WithDepth(2)
+	ns := app.Namespace("mylib")
+	require.NotSame(t, app, ns)
+	require.NoError(t, ns.Load())
+
+	assert.Equal(t, "mylib local", ns.Getenv(allEnvVars[0]))
+	assert.Equal(t, "mylib", ns.Getenv(allEnvVars[1]))
+	assert.Empty(t, os.Getenv(allEnvVars[0]))
+	assert.Equal(t, "host", os.Getenv(allEnvVars[1]))
+
+	require.NoError(t, app.Load())
+	assert.Equal(t, "env", os.Getenv(allEnvVars[0]))
+	assert.Equal(t, []string{".env.mylib.local", ".env.mylib"},
+		ns.CandidateFiles())
+	assert.Equal(t, []string{
+		".env.mylib.test.local", ".env.mylib.local",
+		".env.mylib.test", ".env.mylib",
+	}, New().WithEnvSuffix("test").Namespace("mylib").CandidateFiles())
+}
+
+func TestLoader_Namespace_depth(t *testing.T) {
+	changeDir(t, "testdata/j/k")
+	restoreEnvVars(t)
+
+	ns := New().WithDepth(1).Namespace("mylib")
+	require.NoError(t, ns.Load())
+	assert.Empty(t, ns.Getenv(allEnvVars[0]))
+}
+
+func TestLoader_Namespace_WithoutLocalFiles(t *testing.T) {
+	changeDir(t, "testdata/j/k")
+	restoreEnvVars(t)
+
+	ns := New().WithDepth(2).WithoutLocalFiles().Namespace("mylib")
+	require.NoError(t, ns.Load())
+	assert.Equal(t, "mylib", ns.Getenv(allEnvVars[0]))
+	assert.Equal(t, []string{".env.mylib"}, ns.CandidateFiles())
+}
+
+func TestLoader_Namespace_WithFS(t *testing.T) {
+	restoreEnvVars(t)
+
+	fsys := newTestFS()
+	fsys["app/.env.mylib"] = &fstest.MapFile{Data: []byte("TEST_VAR1=mylib\n")}
+	scoped, err := New().WithFS(fsys).Namespace("mylib").For("app")
+	require.NoError(t, err)
+	assert.Equal(t, "mylib", scoped.Getenv(allEnvVars[0]))
+	assert.Equal(t, Stop{Reason: StopFound, Dir: "/app"}, scoped.Result().Stop)
+}
+
+func TestLoader_Namespace_invalid(t *testing.T) {
+	for _, name := range []string{"", "a/b"} {
+		t.Run(name, func(t *testing.T) {
+			require.ErrorIs(t, New().Namespace(name).Validate(), ErrInvalidConfig)
+		})
+	}
+}
+
+func TestLoader_Namespace_copiesFields(t *testing.T) {
+	var app Loader
+	fillFields(t, reflect.ValueOf(&app).Elem())
+	ns := app.Namespace("mylib")
+
+	reset := map[string]bool{
+		// state of loads
+		"stats": true, "stop": true, "warnings": true, "aead": true,
+		"loaded": true, "sources": true, "winners": true, "precedence": true,
+		"provenance": true, "skipped": true, "envDir": true, "flags": true,
+		"lastPath": true, "dirCache": true, "vars": true, "pending": true,
+		"lastDir": true, "result": true, "recording": true, "ctx": true,
+
+		// settings of application
+		"files": true, "fileOrder": true, "required": true,
+		"requiredFiles": true, "mustFind": true, "schema": true,
+		"schemaErr": true, "values": true, "snapshotFallback": true,
+		"featureFlags": true,
+	}
+	changed := map[string]bool{
+		"namespace": true, "namespaced": true, "envFilesFn": true,
+		"hermetic": true, "noSetenv": true, "hermeticAllow": true,
+	}
+
+	appValue, nsValue := reflect.ValueOf(&app).Elem(), reflect.ValueOf(ns).Elem()
+	for i := range appValue.NumField() {
+		name := appValue.Type().Field(i).Name
+		want, got := unexported(appValue.Field(i)), unexported(nsValue.Field(i))
+		switch {
+		case changed[name]:
+		case reset[name]:
+			assert.True(t, got.IsZero(), "%v must be reset", name)
+		case got.Kind() == reflect.Func:
+			assert.Equal(t, want.Pointer(), got.Pointer(), "%v isn't copied", name)
+		default:
+			assert.Equal(t, want.Interface(), got.Interface(), "%v isn't copied",
+				name)
+		}
+	}
+	assert.Equal(t, "mylib", ns.namespace)
+	assert.True(t, ns.hermetic)
+}
+
+type testKeyProvider struct{}
+
+func (testKeyProvider) Key() ([]byte, error) { return nil, nil }
+
+// unexported returns settable rv, even if it's an unexported field.
+func unexported(rv reflect.Value) reflect.Value {
+	return reflect.NewAt(rv.Type(), unsafe.Pointer(rv.UnsafeAddr())).Elem()
+}
+
+// fillFields sets every field of struct rv to a non-zero value.
+func fillFields(t *testing.T, rv reflect.Value) {
+	t.Helper()
+	for i := range rv.NumField() {
+		f := unexported(rv.Field(i))
+		f.Set(nonZero(t, f.Type()))
+		require.False(t, f.IsZero(), rv.Type().Field(i).Name)
+	}
+}
+
+// nonZero returns a non-zero value of type rt.
+func nonZero(t *testing.T, rt reflect.Type) reflect.Value {
+	t.Helper()
+	rv := reflect.New(rt).Elem()
+	switch rt.Kind() { //nolint:exhaustive // the rest isn't used by Loader
+	case reflect.Bool:
+		rv.SetBool(true)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		rv.SetInt(1)
+	case reflect.String:
+		rv.SetString("x")
+	case reflect.Pointer:
+		rv.Set(reflect.New(rt.Elem()))
+	case reflect.Slice:
+		rv.Set(reflect.MakeSlice(rt, 1, 1))
+		rv.Index(0).Set(nonZero(t, rt.Elem()))
+	case reflect.Map:
+		rv.Set(reflect.MakeMap(rt))
+		rv.SetMapIndex(nonZero(t, rt.Key()), nonZero(t, rt.Elem()))
+	case reflect.Func:
+		rv.Set(reflect.MakeFunc(rt, func([]reflect.Value) []reflect.Value {
+			return nil
+		}))
+	case reflect.Struct:
+		fillFields(t, rv)
+	case reflect.Interface:
+		block, err := aes.NewCipher(make([]byte, 32))
+		require.NoError(t, err)
+		aead, err := cipher.NewGCM(block)
+		require.NoError(t, err)
+		for _, v := range []any{
+			stdFiler{}, fstest.MapFS{}, testKeyProvider{}, aead,
+			mapProcessEnv{}, stdClock{}, errors.New("x"), context.Background(),
+		} {
+			if reflect.TypeOf(v).Implements(rt) {
+				rv.Set(reflect.ValueOf(v))
+				return rv
+			}
+		}
+		t.Fatalf("no value for %v", rt)
+	default:
+		t.Fatalf("unexpected kind of %v", rt)
+	}
+	return rv
+}
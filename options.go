@@ -0,0 +1,106 @@
+package dotenv
+
+// Options for [New] below are equivalents of chained setters of [Loader] with
+// the same names. Options can be collected into reusable slices and passed
+// across packages without exposing the mutable [Loader]:
+//
+//	var testEnv = []dotenv.Option{
+//		dotenv.WithEnvSuffix("test"),
+//		dotenv.WithRootFiles("go.mod", ".git"),
+//	}
+//
+//	env := dotenv.New(testEnv...)
+
+// WithDepth is an [Option] version of [Loader.WithDepth].
+func WithDepth(n int) Option { return func(l *Loader) { l.WithDepth(n) } }
+
+// WithMaxDirs is an [Option] version of [Loader.WithMaxDirs].
+func WithMaxDirs(n int) Option { return func(l *Loader) { l.WithMaxDirs(n) } }
+
+// WithEnvVarName is an [Option] version of [Loader.WithEnvVarName]. It reads
+// the environment variable when [New] is called.
+func WithEnvVarName(s string) Option {
+	return func(l *Loader) { l.WithEnvVarName(s) }
+}
+
+// WithEnvSuffix is an [Option] version of [Loader.WithEnvSuffix].
+func WithEnvSuffix(s string) Option {
+	return func(l *Loader) { l.WithEnvSuffix(s) }
+}
+
+// WithFiles is an [Option] version of [Loader.WithFiles].
+func WithFiles(fnames ...string) Option {
+	return func(l *Loader) { l.WithFiles(fnames...) }
+}
+
+// WithFileOrder is an [Option] version of [Loader.WithFileOrder].
+func WithFileOrder(fn func(envName string) []string) Option {
+	return func(l *Loader) { l.WithFileOrder(fn) }
+}
+
+// WithFileName is an [Option] version of [Loader.WithFileName].
+func WithFileName(fname string) Option {
+	return func(l *Loader) { l.WithFileName(fname) }
+}
+
+// WithoutLocalFiles is an [Option] version of [Loader.WithoutLocalFiles].
+func WithoutLocalFiles() Option {
+	return func(l *Loader) { l.WithoutLocalFiles() }
+}
+
+// WithRootDir is an [Option] version of [Loader.WithRootDir].
+func WithRootDir(path string) Option {
+	return func(l *Loader) { l.WithRootDir(path) }
+}
+
+// WithClampRootDir is an [Option] version of [Loader.WithClampRootDir].
+func WithClampRootDir() Option { return func(l *Loader) { l.WithClampRootDir() } }
+
+// WithFallbackStartDir is an [Option] version of
+// [Loader.WithFallbackStartDir].
+func WithFallbackStartDir(path string) Option {
+	return func(l *Loader) { l.WithFallbackStartDir(path) }
+}
+
+// WithRootFiles is an [Option] version of [Loader.WithRootFiles].
+func WithRootFiles(fnames ...string) Option {
+	return func(l *Loader) { l.WithRootFiles(fnames...) }
+}
+
+// WithIgnoreFile is an [Option] version of [Loader.WithIgnoreFile].
+func WithIgnoreFile(fname string) Option {
+	return func(l *Loader) { l.WithIgnoreFile(fname) }
+}
+
+// WithPreset is an [Option] version of [Loader.WithPreset].
+func WithPreset(p Preset) Option { return func(l *Loader) { l.WithPreset(p) } }
+
+// WithoutSetenv is an [Option] version of [Loader.WithoutSetenv].
+func WithoutSetenv() Option { return func(l *Loader) { l.WithoutSetenv() } }
+
+// WithHermetic is an [Option] version of [Loader.WithHermetic].
+func WithHermetic(allow ...string) Option {
+	return func(l *Loader) { l.WithHermetic(allow...) }
+}
+
+// WithOverride is an [Option] version of [Loader.WithOverride].
+func WithOverride() Option { return func(l *Loader) { l.WithOverride() } }
+
+// WithContinueOnError is an [Option] version of [Loader.WithContinueOnError].
+func WithContinueOnError() Option {
+	return func(l *Loader) { l.WithContinueOnError() }
+}
+
+// WithMiddleware is an [Option] version of [Loader.WithMiddleware].
+func WithMiddleware(mws ...Middleware) Option {
+	return func(l *Loader) { l.WithMiddleware(mws...) }
+}
+
+// With configures [Loader] by opts, like [New] does, so the same options can be
+// applied to already created [Loader].
+func (self *Loader) With(opts ...Option) *Loader {
+	for _, opt := range opts {
+		opt(self)
+	}
+	return self
+}
@@ -0,0 +1,62 @@
+package dotenv
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNew_options(t *testing.T) {
+	t.Setenv("TEST_ENV_NAME", "staging")
+	rootDir, err := filepath.Abs("testdata")
+	require.NoError(t, err)
+
+	opts := []Option{
+		WithDepth(2),
+		WithMaxDirs(10),
+		WithEnvVarName("TEST_ENV_NAME"),
+		WithRootDir("testdata"),
+		WithClampRootDir(),
+		WithFallbackStartDir("/"),
+		WithRootFiles(".git"),
+		WithIgnoreFile(".skip"),
+		WithPreset(PresetVite),
+		WithHermetic("FOO"),
+		WithOverride(),
+		WithContinueOnError(),
+		WithMiddleware(func(next LoadFunc) LoadFunc { return next }),
+	}
+
+	got := New(opts...)
+	want := New().WithDepth(2).WithMaxDirs(10).WithEnvSuffix("staging").
+		WithRootDir("testdata").WithClampRootDir().WithFallbackStartDir("/").
+		WithRootFiles(".git").WithIgnoreFile(".skip").WithPreset(PresetVite).
+		WithHermetic("FOO").WithOverride().WithContinueOnError()
+
+	assert.Equal(t, 2, got.lookupDepth)
+	assert.Equal(t, 10, got.maxDirs)
+	assert.Equal(t, "staging", got.envSuffix)
+	assert.Equal(t, rootDir, got.rootDir)
+	assert.True(t, got.clampRootDir)
+	assert.Equal(t, "/", got.fallbackStartDir)
+	assert.Equal(t, []string{".git"}, got.rootFiles)
+	assert.Equal(t, ".skip", got.ignoreFile)
+	assert.Equal(t, PresetVite, got.preset)
+	assert.Equal(t, want.CandidateFiles(), got.CandidateFiles())
+	assert.Equal(t, want.hermeticAllow, got.hermeticAllow)
+	assert.True(t, got.noSetenv)
+	assert.True(t, got.override)
+	assert.True(t, got.continueOnError)
+	assert.Len(t, got.middlewares, 1)
+
+	assert.Equal(t, "test", New(WithEnvSuffix("test"), WithoutSetenv()).envSuffix)
+}
+
+func TestLoader_With(t *testing.T) {
+	env := New()
+	assert.Same(t, env, env.With(WithEnvSuffix("test"), WithDepth(1)))
+	assert.Equal(t, "test", env.envSuffix)
+	assert.Equal(t, 1, env.lookupDepth)
+}
@@ -0,0 +1,229 @@
+package dotenv
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/joho/godotenv"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoader_Load_order(t *testing.T) {
+	tests := []struct {
+		name   string
+		before func(t *testing.T, env *Loader)
+		expect []string
+	}{
+		{
+			name:   "first file wins",
+			expect: []string{"local", "second"},
+		},
+		{
+			name: "env specific file",
+			before: func(t *testing.T, env *Loader) {
+				env.WithEnvSuffix("test")
+			},
+			expect: []string{"local", "test"},
+		},
+		{
+			name: "last definition in file wins",
+			before: func(t *testing.T, env *Loader) {
+				env.WithPreset(PresetFlask)
+			},
+			expect: []string{"last", "second"},
+		},
+		{
+			name: "process env wins",
+			before: func(t *testing.T, env *Loader) {
+				t.Setenv(allEnvVars[1], "env")
+			},
+			expect: []string{"local", "env"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			changeDir(t, "testdata/g")
+			restoreEnvVars(t)
+			env := New()
+			if tt.before != nil {
+				tt.before(t, env)
+			}
+			require.NoError(t, env.Load())
+			for i, name := range allEnvVars {
+				assert.Equal(t, tt.expect[i], os.Getenv(name), name)
+			}
+		})
+	}
+}
+
+func TestLoader_Load_orderOfWarnings(t *testing.T) {
+	changeDir(t, "testdata/g")
+	restoreEnvVars(t)
+
+	var keys []string
+	env := New().WithEnvSuffix("test")
+	env.setenvFn = func(key, value string) error {
+		keys = append(keys, key+"="+value)
+		return errors.New("read-only")
+	}
+
+	for range 10 {
+		keys = keys[:0]
+		env.vars = nil
+		require.NoError(t, env.Load())
+		assert.Equal(t, []string{"TEST_VAR1=local", "TEST_VAR2=test"}, keys)
+		require.Len(t, env.Result().Warnings, 2)
+	}
+}
+
+func TestLoader_Precedence(t *testing.T) {
+	changeDir(t, "testdata/g")
+	restoreEnvVars(t)
+
+	env := New().WithEnvSuffix("test")
+	assert.Nil(t, env.Precedence())
+
+	require.NoError(t, env.Load())
+	precedence := env.Precedence()
+	assert.Equal(t, []SourceRef{
+		{Kind: SourceEnv},
+		{Kind: SourceFile, Name: ".env.local"},
+		{Kind: SourceFile, Name: ".env.test"},
+		{Kind: SourceFile, Name: ".env"},
+	}, precedence)
+
+	// Every env var comes from the first source in precedence order, which
+	// defines it.
+	for key, fname := range env.sources {
+		i := slices.IndexFunc(precedence, func(ref SourceRef) bool {
+			return ref.Name == fname
+		})
+		require.Positive(t, i, key)
+		for _, ref := range precedence[1:i] {
+			vars, err := godotenv.Read(ref.Name)
+			require.NoError(t, err)
+			assert.NotContains(t, vars, key, ref)
+		}
+	}
+
+	precedence[0].Name = "changed"
+	assert.Empty(t, env.Precedence()[0].Name)
+}
+
+func TestSourceRef_String(t *testing.T) {
+	assert.Equal(t, "env", SourceRef{Kind: SourceEnv}.String())
+	assert.Equal(t, "file:.env",
+		SourceRef{Kind: SourceFile, Name: ".env"}.String())
+	assert.Equal(t, "SourceKind(10)", SourceKind(10).String())
+}
+
+func TestLoader_WithSourcePriority(t *testing.T) {
+	changeDir(t, "testdata/g")
+	restoreEnvVars(t)
+
+	env := New().WithEnvSuffix("test")
+	assert.Same(t, env, env.WithSourcePriority(".env", 50))
+	assert.Same(t, env, env.WithSourcePriority(".env.test", 50))
+	require.NoError(t, env.Load())
+
+	assert.Equal(t, []SourceRef{
+		{Kind: SourceEnv},
+		{Kind: SourceFile, Name: ".env.test"},
+		{Kind: SourceFile, Name: ".env"},
+		{Kind: SourceFile, Name: ".env.local"},
+	}, env.Precedence())
+	assert.Equal(t, "last", os.Getenv(allEnvVars[0]))
+	assert.Equal(t, "test", os.Getenv(allEnvVars[1]))
+}
+
+func TestLoader_Load_sameFile(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, ".env"),
+		[]byte("TEST_VAR1=env\n"), 0o600))
+	if err := os.Symlink(".env", filepath.Join(dir, ".env.local")); err != nil {
+		t.Skip(err)
+	}
+	changeDir(t, dir)
+	restoreEnvVars(t)
+
+	env := New()
+	require.NoError(t, env.Load())
+	assert.Equal(t, "env", os.Getenv(allEnvVars[0]))
+	assert.Equal(t, []SourceRef{
+		{Kind: SourceEnv},
+		{Kind: SourceFile, Name: ".env.local"},
+	}, env.Precedence())
+	assert.Len(t, env.Result().Stats.Files, 1)
+	assert.Equal(t, []SkippedFile{{Name: ".env", Reason: "same file as .env.local"}},
+		env.Result().Skipped)
+}
+
+func TestUniqueFiles(t *testing.T) {
+	assert.Equal(t, []string{"not-exists", "not-exists"},
+		New().uniqueFiles([]string{"not-exists", "not-exists"}))
+	assert.Equal(t, []string{"order_test.go"},
+		New().uniqueFiles([]string{"order_test.go", "./order_test.go"}))
+}
+
+func TestLoader_WithValues(t *testing.T) {
+	tests := []struct {
+		name       string
+		dir        string
+		priority   int
+		expect     []string
+		precedence []SourceRef
+	}{
+		{
+			name:   "above files",
+			dir:    "testdata/g",
+			expect: []string{"values", "second"},
+			precedence: []SourceRef{
+				{Kind: SourceEnv},
+				{Kind: SourceValues},
+				{Kind: SourceFile, Name: ".env.local"},
+				{Kind: SourceFile, Name: ".env"},
+			},
+		},
+		{
+			name:     "below files",
+			dir:      "testdata/g",
+			priority: -1,
+			expect:   []string{"local", "second"},
+			precedence: []SourceRef{
+				{Kind: SourceEnv},
+				{Kind: SourceFile, Name: ".env.local"},
+				{Kind: SourceFile, Name: ".env"},
+				{Kind: SourceValues},
+			},
+		},
+		{
+			name:       "without files",
+			dir:        t.TempDir(),
+			expect:     []string{"values", ""},
+			precedence: []SourceRef{{Kind: SourceEnv}, {Kind: SourceValues}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			changeDir(t, tt.dir)
+			restoreEnvVars(t)
+			env := New().WithRootDir(".")
+			assert.Same(t, env,
+				env.WithValues(map[string]string{allEnvVars[0]: "values"}))
+			if tt.priority != 0 {
+				env.WithSourcePriority(ValuesSource, tt.priority)
+			}
+			require.NoError(t, env.Load())
+			for i, name := range allEnvVars {
+				assert.Equal(t, tt.expect[i], os.Getenv(name), name)
+			}
+			assert.Equal(t, tt.precedence, env.Precedence())
+		})
+	}
+}
@@ -0,0 +1,25 @@
+package dotenv
+
+// WithOverride configures [Loader.Load] to override existing env vars by values
+// of .env files. The cascade of .env files stays the same, so the first file,
+// which defines a variable, still wins, but the process environment has the
+// lowest priority now and [Loader.Precedence] returns [SourceEnv] as the last
+// source. It's useful for test runners and container entrypoints, which must
+// get values of .env files regardless of inherited environment.
+func (self *Loader) WithOverride() *Loader {
+	self.override = true
+	return self
+}
+
+// Overload loads .env files like [Loader.Load] does, but values of .env files
+// override existing env vars of the process environment, see
+// [Loader.WithOverride]. The cascade of .env files stays the same, so the
+// first file, which defines a variable, still wins, and [Result] reports it as
+// the source. It overrides only during this call, regardless of configuration
+// of [Loader].
+func (self *Loader) Overload(callbacks ...func() error) error {
+	override := self.override
+	self.override = true
+	defer func() { self.override = override }()
+	return self.Load(callbacks...)
+}
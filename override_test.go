@@ -0,0 +1,68 @@
+package dotenv
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoader_WithOverride(t *testing.T) {
+	changeDir(t, "testdata/g")
+	restoreEnvVars(t)
+	t.Setenv(allEnvVars[0], "env")
+	t.Setenv(allEnvVars[1], "env")
+
+	env := New().WithEnvSuffix("test")
+	assert.Same(t, env, env.WithOverride())
+	require.NoError(t, env.Load())
+
+	assert.Equal(t, "local", os.Getenv(allEnvVars[0]))
+	assert.Equal(t, "test", os.Getenv(allEnvVars[1]))
+	assert.Equal(t, []SourceRef{
+		{Kind: SourceFile, Name: ".env.local"},
+		{Kind: SourceFile, Name: ".env.test"},
+		{Kind: SourceFile, Name: ".env"},
+		{Kind: SourceEnv},
+	}, env.Precedence())
+
+	provenance := env.Result().provenance
+	require.Len(t, provenance, 2)
+	assert.Equal(t, Provenance{
+		Key:        allEnvVars[1],
+		Source:     "file:.env.test",
+		Overrode:   true,
+		Overridden: []string{"file:.env"},
+		fname:      ".env.test",
+	}, provenance[1])
+}
+
+func TestLoader_Overload(t *testing.T) {
+	changeDir(t, "testdata/g")
+	restoreEnvVars(t)
+	t.Setenv(allEnvVars[0], "env")
+
+	env := New()
+	var called bool
+	require.NoError(t, env.Overload(func() error {
+		called = true
+		return nil
+	}))
+	assert.True(t, called)
+	assert.Equal(t, "local", os.Getenv(allEnvVars[0]))
+	assert.False(t, env.override)
+
+	t.Setenv(allEnvVars[0], "env")
+	require.NoError(t, env.Load())
+	assert.Equal(t, "env", os.Getenv(allEnvVars[0]))
+}
+
+func TestOverload(t *testing.T) {
+	changeDir(t, "testdata/g")
+	restoreEnvVars(t)
+	t.Setenv(allEnvVars[0], "env")
+
+	require.NoError(t, Overload())
+	assert.Equal(t, "local", os.Getenv(allEnvVars[0]))
+}
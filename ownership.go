@@ -0,0 +1,70 @@
+package dotenv
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// ErrForeignOwner returned by [Loader.Load] if it's configured by
+// [Loader.WithStrictOwnership] and a .env file outside of home dir is owned by
+// another user.
+var ErrForeignOwner = errors.New("file owned by another user")
+
+// WithStrictOwnership configures [Loader.Load] to refuse loading of .env files
+// outside of home dir of current user, which are owned by another user, except
+// root. Lookup can walk above home dir or project dir into shared dirs, like
+// /tmp or /var, and this option prevents privilege escalation by a planted .env
+// file on multi-user hosts:
+//
+//	env := dotenv.New().WithStrictOwnership()
+//
+// Load returns an error wrapping [ErrForeignOwner] for such files. Ownership
+// isn't checked on platforms without owners of files, like Windows, and for
+// files of [Loader.WithFS].
+func (self *Loader) WithStrictOwnership() *Loader {
+	self.strictOwner = true
+	return self
+}
+
+// checkOwner returns an error if it's configured by
+// [Loader.WithStrictOwnership] and fname is outside of home dir and owned by
+// another user. Symlinks are resolved first, so a symlink inside of home dir
+// doesn't exempt a file outside of it.
+func (self *Loader) checkOwner(fname string) error {
+	if !self.strictOwner || self.fsys != nil {
+		return nil
+	}
+
+	realName, err := filepath.EvalSymlinks(fname)
+	if err != nil {
+		return fmt.Errorf("check owner of %v: %w", fname, err)
+	} else if realName, err = filepath.Abs(realName); err != nil {
+		return fmt.Errorf("check owner of %v: %w", fname, err)
+	} else if home, ok := realHomeDir(); ok && isAncestor(home, realName) {
+		return nil
+	}
+
+	fi, err := os.Stat(fname)
+	if err != nil {
+		return fmt.Errorf("check owner of %v: %w", fname, err)
+	} else if uid, ok := fileOwner(fi); ok && uid != 0 && uid != os.Getuid() {
+		return fmt.Errorf("%w: %v is owned by uid %v", ErrForeignOwner, fname,
+			uid)
+	}
+	return nil
+}
+
+// realHomeDir returns home dir of current user with resolved symlinks.
+func realHomeDir() (string, bool) {
+	home, err := os.UserHomeDir()
+	if err != nil || home == "" {
+		return "", false
+	} else if home, err = filepath.EvalSymlinks(home); err != nil {
+		return "", false
+	} else if home, err = filepath.Abs(home); err != nil {
+		return "", false
+	}
+	return home, true
+}
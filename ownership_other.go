@@ -0,0 +1,8 @@
+//go:build !unix
+
+package dotenv
+
+import "os"
+
+// fileOwner returns false, because files have no owners on this platform.
+func fileOwner(fi os.FileInfo) (int, bool) { return 0, false }
@@ -0,0 +1,79 @@
+package dotenv
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoader_WithStrictOwnership(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("files have no owners")
+	} else if os.Getuid() != 0 {
+		t.Skip("needs root to chown files")
+	}
+
+	shared := valueNoError[string](t)(filepath.EvalSymlinks(t.TempDir()))
+	projDir := filepath.Join(shared, "proj")
+	require.NoError(t, os.Mkdir(projDir, 0o700))
+	fname := filepath.Join(shared, ".env")
+	require.NoError(t, os.WriteFile(fname, []byte("TEST_VAR1=planted\n"), 0o644))
+	require.NoError(t, os.Chown(fname, 12345, 12345))
+	changeDir(t, projDir)
+	restoreEnvVars(t)
+	t.Setenv("HOME", filepath.Join(shared, "home"))
+
+	require.NoError(t, New().WithoutSetenv().Load())
+
+	env := New().WithoutSetenv()
+	assert.Same(t, env, env.WithStrictOwnership())
+	require.ErrorIs(t, env.Load(), ErrForeignOwner)
+	assert.Empty(t, env.Getenv(allEnvVars[0]))
+
+	t.Setenv("HOME", shared)
+	env = New().WithoutSetenv().WithStrictOwnership()
+	require.NoError(t, env.Load())
+	assert.Equal(t, "planted", env.Getenv(allEnvVars[0]))
+
+	t.Setenv("HOME", filepath.Join(shared, "home"))
+	require.NoError(t, os.Chown(fname, os.Getuid(), os.Getgid()))
+	require.NoError(t, New().WithoutSetenv().WithStrictOwnership().Load())
+}
+
+func TestLoader_WithStrictOwnership_symlink(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("files have no owners")
+	} else if os.Getuid() != 0 {
+		t.Skip("needs root to chown files")
+	}
+
+	shared := valueNoError[string](t)(filepath.EvalSymlinks(t.TempDir()))
+	plantedDir := filepath.Join(shared, "planted")
+	homeDir := filepath.Join(shared, "home")
+	projDir := filepath.Join(homeDir, "proj")
+	require.NoError(t, os.MkdirAll(plantedDir, 0o700))
+	require.NoError(t, os.MkdirAll(projDir, 0o700))
+	fname := filepath.Join(plantedDir, ".env")
+	require.NoError(t, os.WriteFile(fname, []byte("TEST_VAR1=planted\n"), 0o644))
+	require.NoError(t, os.Chown(fname, 12345, 12345))
+	require.NoError(t, os.Symlink(fname, filepath.Join(projDir, ".env")))
+	require.NoError(t, os.WriteFile(filepath.Join(projDir, "go.mod"), nil,
+		0o600))
+	changeDir(t, projDir)
+	restoreEnvVars(t)
+
+	t.Setenv("HOME", homeDir)
+	env := New().WithoutSetenv().WithStrictOwnership()
+	require.ErrorIs(t, env.Load(), ErrForeignOwner)
+
+	homeLink := filepath.Join(shared, "home-link")
+	require.NoError(t, os.Symlink(plantedDir, homeLink))
+	t.Setenv("HOME", homeLink)
+	env = New().WithoutSetenv().WithStrictOwnership()
+	require.NoError(t, env.Load())
+	assert.Equal(t, "planted", env.Getenv(allEnvVars[0]))
+}
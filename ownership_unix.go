@@ -0,0 +1,16 @@
+//go:build unix
+
+package dotenv
+
+import (
+	"os"
+	"syscall"
+)
+
+// fileOwner returns uid of owner of file fi.
+func fileOwner(fi os.FileInfo) (int, bool) {
+	if st, ok := fi.Sys().(*syscall.Stat_t); ok {
+		return int(st.Uid), true
+	}
+	return 0, false
+}
@@ -0,0 +1,164 @@
+package dotenv
+
+import (
+	"cmp"
+	"math"
+	"path/filepath"
+	"slices"
+	"strconv"
+)
+
+// SourceKind is a kind of source of env vars.
+type SourceKind int
+
+const (
+	// SourceEnv is the environment of current process. Its env vars always win
+	// over all other sources.
+	SourceEnv SourceKind = iota
+
+	// SourceFile is a .env file.
+	SourceFile
+
+	// SourceSnapshot is a snapshot file, configured by
+	// [Loader.WithSnapshotFallback].
+	SourceSnapshot
+
+	// SourceValues is env vars, configured by [Loader.WithValues].
+	SourceValues
+
+	// SourceReader is in-memory content of [Loader.LoadFrom] and
+	// [Loader.LoadString].
+	SourceReader
+)
+
+// ValuesSource is a name of env vars, configured by [Loader.WithValues], for
+// [Loader.WithSourcePriority].
+const ValuesSource = "values"
+
+var sourceKindNames = [...]string{
+	SourceEnv:      "env",
+	SourceFile:     "file",
+	SourceSnapshot: "snapshot",
+	SourceValues:   "values",
+	SourceReader:   "reader",
+}
+
+// String returns human readable name of kind.
+func (self SourceKind) String() string {
+	if self >= 0 && int(self) < len(sourceKindNames) {
+		return sourceKindNames[self]
+	}
+	return "SourceKind(" + strconv.Itoa(int(self)) + ")"
+}
+
+// SourceRef references a source of env vars.
+type SourceRef struct {
+	// Kind is a kind of source.
+	Kind SourceKind
+
+	// Name is a name of source, like path of .env file. It's empty for
+	// [SourceEnv] and [SourceValues].
+	Name string
+}
+
+// String returns name of source or its kind, if it has no name.
+func (self SourceRef) String() string {
+	if self.Name == "" {
+		return self.Kind.String()
+	}
+	return self.Kind.String() + ":" + self.Name
+}
+
+// isFile returns true if the source is a file, which can be read by name.
+func (self SourceRef) isFile() bool {
+	return self.Name != "" &&
+		(self.Kind == SourceFile || self.Kind == SourceSnapshot)
+}
+
+// Precedence returns resolution order of sources, applied by last
+// [Loader.Load]. The first source wins: if an env var is defined by some
+// source, all next sources can't change it. The first item is always
+// [SourceEnv], followed by every loaded .env file in cascade order, or the last
+// one with [Loader.WithOverride].
+//
+// Load applies sources exactly in this order, so it can be used by tests, which
+// pin configuration contract of an application:
+//
+//	require.NoError(t, env.Load())
+//	assert.Equal(t, []dotenv.SourceRef{
+//		{Kind: dotenv.SourceEnv},
+//		{Kind: dotenv.SourceFile, Name: ".env.local"},
+//		{Kind: dotenv.SourceFile, Name: ".env"},
+//	}, env.Precedence())
+//
+// It returns nil before the first Load.
+func (self *Loader) Precedence() []SourceRef {
+	return slices.Clone(self.precedence)
+}
+
+// WithSourcePriority configures [Loader.Load] to apply .env file with base name
+// fname with given priority. Files with higher priority win over files with
+// lower priority. Files without configured priority have priority 0 and files
+// with the same priority are applied in cascade order. fname can be
+// [ValuesSource] too, see [Loader.WithValues]. For instance:
+//
+//	env := dotenv.New().
+//		WithSourcePriority(".env", 50).
+//		WithSourcePriority(".env.local", 10)
+//
+// makes .env win over .env.local. The process environment always wins over all
+// .env files, unless [Loader.WithOverride] is configured. See also
+// [Loader.Precedence].
+func (self *Loader) WithSourcePriority(fname string, priority int) *Loader {
+	if self.priorities == nil {
+		self.priorities = make(map[string]int)
+	}
+	self.priorities[fname] = priority
+	return self
+}
+
+// WithValues configures [Loader.Load] to apply env vars from vars, like they
+// are defined by a .env file. By default they are applied before all .env
+// files, so they win over .env files, but not over the process environment. Use
+// [Loader.WithSourcePriority] with [ValuesSource] to apply them at another
+// precedence:
+//
+//	env := dotenv.New().
+//		WithValues(map[string]string{"PORT": *flagPort}).
+//		WithSourcePriority(dotenv.ValuesSource, -1)
+//
+// makes them lose to .env files. It's useful for tests and overrides from
+// command line flags, which go through the same validation and hooks.
+func (self *Loader) WithValues(vars map[string]string) *Loader {
+	self.values = vars
+	return self
+}
+
+// sortByPriority sorts refs by priority, keeping cascade order of sources with
+// the same priority.
+func (self *Loader) sortByPriority(refs []SourceRef) {
+	if len(self.priorities) == 0 {
+		return
+	}
+	slices.SortStableFunc(refs, func(a, b SourceRef) int {
+		return cmp.Compare(self.priority(b), self.priority(a))
+	})
+}
+
+// priority returns priority of source ref.
+func (self *Loader) priority(ref SourceRef) int {
+	if ref.Kind != SourceValues {
+		return self.priorities[filepath.Base(ref.Name)]
+	} else if p, ok := self.priorities[ValuesSource]; ok {
+		return p
+	}
+	return math.MaxInt
+}
+
+// applySource appends ref to resolution order and loads it.
+func (self *Loader) applySource(ref SourceRef) error {
+	self.precedence = append(self.precedence, ref)
+	err := self.loadFile(ref)
+	self.traceFile(ref, err)
+	return err
+}
@@ -0,0 +1,112 @@
+package dotenv
+
+// Preset defines names and order of .env files, used by some ecosystem. See
+// [Loader.WithPreset].
+type Preset int
+
+const (
+	// PresetDefault is the default [original rules]:
+	//
+	//  1. .env.ENV.local
+	//  2. .env.local
+	//  3. .env.ENV
+	//  4. .env
+	//
+	// [original rules]: https://github.com/bkeepers/dotenv#what-other-env-files-can-i-use
+	PresetDefault Preset = iota
+
+	// PresetVite is like [Vite] does, mode specific files have priority over
+	// ".env.local":
+	//
+	//  1. .env.ENV.local
+	//  2. .env.ENV
+	//  3. .env.local
+	//  4. .env
+	//
+	// [Vite]: https://vite.dev/guide/env-and-mode
+	PresetVite
+
+	// PresetRails is like [dotenv-rails] does. It's the same as
+	// [PresetDefault], but ".env.local" isn't loaded in "test" environment.
+	//
+	// [dotenv-rails]: https://github.com/bkeepers/dotenv#customizing-rails
+	PresetRails
+
+	// PresetNext is like [Next.js] does. It's the same as [PresetRails].
+	//
+	// [Next.js]: https://nextjs.org/docs/app/guides/environment-variables
+	PresetNext
+
+	// PresetFlask is like [Flask] does. It has no cascade and name of
+	// environment is ignored:
+	//
+	//  1. .env
+	//  2. .flaskenv
+	//
+	// [Flask]: https://flask.palletsprojects.com/en/stable/cli/#environment-variables-from-dotenv
+	PresetFlask
+
+	// PresetHeroku is like [heroku local] does. It has no cascade, name of
+	// environment is ignored and lookup stops at dir with Procfile, besides of
+	// configured root files:
+	//
+	//  1. .env
+	//
+	// The last definition of a key in .env wins, like it always does.
+	//
+	// [heroku local]: https://devcenter.heroku.com/articles/heroku-local
+	PresetHeroku
+)
+
+// WithPreset configures [Loader.Load] to search for .env files with names and
+// in order of p, instead of default one. For instance:
+//
+//	env := dotenv.New().WithPreset(dotenv.PresetVite).WithEnvSuffix("staging")
+//
+// searches for ".env.staging.local", ".env.staging", ".env.local" and ".env".
+func (self *Loader) WithPreset(p Preset) *Loader {
+	self.preset = p
+	if p == PresetDefault {
+		self.envFilesFn = nil
+	} else {
+		self.envFilesFn = p.envFiles
+	}
+	return self
+}
+
+// envFiles returns list of .env files for envName according to preset.
+func (self Preset) envFiles(envName string) []string {
+	switch self {
+	case PresetFlask:
+		return []string{".env", ".flaskenv"}
+	case PresetHeroku:
+		return []string{".env"}
+	case PresetVite:
+		if envName == "" {
+			return []string{".env.local", ".env"}
+		}
+		return []string{
+			".env." + envName + ".local", ".env." + envName,
+			".env.local", ".env",
+		}
+	case PresetRails, PresetNext:
+		if envName == "" {
+			return []string{".env.local", ".env"}
+		} else if envName == "test" {
+			return []string{".env.test.local", ".env.test", ".env"}
+		}
+	}
+
+	return []string{
+		".env." + envName + ".local", ".env.local",
+		".env." + envName, ".env",
+	}
+}
+
+// rootFiles returns names of files, which stop lookup, according to preset.
+func (self Preset) rootFiles() []string {
+	if self == PresetHeroku {
+		return []string{"Procfile"}
+	}
+	return nil
+}
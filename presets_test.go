@@ -0,0 +1,111 @@
+package dotenv
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoader_WithPreset(t *testing.T) {
+	tests := []struct {
+		name    string
+		preset  Preset
+		envName string
+		expect  []string
+	}{
+		{
+			name:    "default",
+			preset:  PresetDefault,
+			envName: "test",
+			expect:  []string{".env.test.local", ".env.local", ".env.test", ".env"},
+		},
+		{
+			name:   "vite without env",
+			preset: PresetVite,
+			expect: []string{".env.local", ".env"},
+		},
+		{
+			name:    "vite",
+			preset:  PresetVite,
+			envName: "staging",
+			expect: []string{
+				".env.staging.local", ".env.staging", ".env.local", ".env",
+			},
+		},
+		{
+			name:   "rails without env",
+			preset: PresetRails,
+			expect: []string{".env.local", ".env"},
+		},
+		{
+			name:    "rails",
+			preset:  PresetRails,
+			envName: "production",
+			expect: []string{
+				".env.production.local", ".env.local", ".env.production", ".env",
+			},
+		},
+		{
+			name:    "rails test",
+			preset:  PresetRails,
+			envName: "test",
+			expect:  []string{".env.test.local", ".env.test", ".env"},
+		},
+		{
+			name:    "next test",
+			preset:  PresetNext,
+			envName: "test",
+			expect:  []string{".env.test.local", ".env.test", ".env"},
+		},
+		{
+			name:    "flask",
+			preset:  PresetFlask,
+			envName: "production",
+			expect:  []string{".env", ".flaskenv"},
+		},
+		{
+			name:    "heroku",
+			preset:  PresetHeroku,
+			envName: "production",
+			expect:  []string{".env"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			env := New()
+			assert.Same(t, env, env.WithPreset(tt.preset))
+			env.WithEnvSuffix(tt.envName)
+			assert.Equal(t, tt.expect, env.envFiles())
+		})
+	}
+}
+
+func TestLoader_WithPreset_reset(t *testing.T) {
+	env := New().WithPreset(PresetFlask)
+	require.NotNil(t, env.envFilesFn)
+	env.WithPreset(PresetDefault)
+	assert.Nil(t, env.envFilesFn)
+	assert.Equal(t, []string{".env.local", ".env"}, env.envFiles())
+}
+
+func TestLoader_WithPreset_heroku(t *testing.T) {
+	curDir := valueNoError[string](t)(os.Getwd())
+	changeDir(t, "testdata/i/j")
+	restoreEnvVars(t)
+
+	env := New().WithPreset(PresetHeroku)
+	require.NoError(t, env.Load())
+	assert.Empty(t, os.Getenv(allEnvVars[0]))
+	assert.Equal(t, Stop{
+		Reason:   StopRootFile,
+		Dir:      filepath.Join(curDir, "testdata", "i"),
+		RootFile: "Procfile",
+	}, env.Result().Stop)
+
+	require.NoError(t, env.WithPreset(PresetDefault).Load())
+	assert.Equal(t, "testdata", os.Getenv(allEnvVars[0]))
+}
@@ -0,0 +1,85 @@
+package dotenv
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+)
+
+// Resolution describes value of an env var, computed by [Loader.ResolveKey].
+type Resolution struct {
+	// Key is a name of env var.
+	Key string
+
+	// Env is a name of environment, like "staging".
+	Env string
+
+	// Value is a value of Key, if Found is true.
+	Value string
+
+	// Found is true if Key is defined by any source.
+	Found bool
+
+	// Provenance describes where Value comes from, if Found is true.
+	Provenance Provenance
+}
+
+// ResolveKey computes value and provenance of env var key, as if name of
+// environment is env, see [Loader.WithEnvSuffix]. It loads .env files of env,
+// but applies nothing, so admin UIs and debugging endpoints can answer "what
+// would this be in staging?":
+//
+//	res, err := env.ResolveKey("DATABASE_URL", "staging")
+//	if err != nil {
+//		return err
+//	}
+//	fmt.Println(res.Value, res.Provenance.Source, res.Provenance.Line)
+//
+// It uses a copy of this [Loader] configuration, like [Loader.For] does. Env
+// vars, applied by last Load of this [Loader], are ignored, so they don't
+// shadow values of env. Other env vars of the process environment still win,
+// unless [Loader.WithOverride] is configured.
+func (self *Loader) ResolveKey(key, env string) (Resolution, error) {
+	l := *self
+	l.envSuffix, l.noSetenv = env, true
+	l.hermetic, l.hermeticAllow = true, self.previewAllow()
+	l.vars, l.lastDir = nil, ""
+	l.result, l.recording, l.trace = nil, nil, nil
+	if err := l.Load(); err != nil {
+		return Resolution{}, fmt.Errorf("resolve %v for %q: %w", key, env, err)
+	}
+
+	res := Resolution{Key: key, Env: env}
+	res.Value, res.Found = l.lookupEnv(key)
+	if !res.Found {
+		return res, nil
+	}
+
+	if p, ok := l.provenance[key]; ok {
+		res.Provenance = *p
+		res.Provenance.Overridden = slices.Clone(p.Overridden)
+		if p.fname != "" {
+			res.Provenance.Line = keyLine(l.fsys, p.fname, key)
+		}
+	} else {
+		res.Provenance = Provenance{
+			Key:    key,
+			Source: SourceRef{Kind: SourceEnv}.String(),
+		}
+	}
+	return res, nil
+}
+
+// previewAllow returns env vars of the process environment, visible for
+// [Loader], except env vars, applied by last Load.
+func (self *Loader) previewAllow() []string {
+	allow := self.hermeticAllow
+	if !self.hermetic {
+		allow = slices.Collect(maps.Keys(environMap(self.procEnv.Environ())))
+	}
+
+	return slices.DeleteFunc(slices.Clone(allow), func(k string) bool {
+		ref, ok := self.winners[k]
+		return ok && ref.Kind != SourceEnv
+	})
+}
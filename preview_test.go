@@ -0,0 +1,47 @@
+package dotenv
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoader_ResolveKey(t *testing.T) {
+	changeDir(t, filepath.Join("testdata", "g"))
+	restoreEnvVars(t)
+
+	env := New().WithEnvSuffix("test")
+	require.NoError(t, env.Load())
+	require.Equal(t, "test", os.Getenv(allEnvVars[1]))
+
+	res, err := env.ResolveKey(allEnvVars[1], "")
+	require.NoError(t, err)
+	assert.Equal(t, Resolution{
+		Key:   allEnvVars[1],
+		Value: "second",
+		Found: true,
+		Provenance: Provenance{
+			Key:    allEnvVars[1],
+			Source: "file:.env",
+			Line:   2,
+			fname:  ".env",
+		},
+	}, res)
+	assert.Equal(t, "test", os.Getenv(allEnvVars[1]))
+
+	res, err = env.ResolveKey("TEST_VAR3", "production")
+	require.NoError(t, err)
+	assert.Equal(t, Resolution{Key: "TEST_VAR3", Env: "production"}, res)
+
+	t.Setenv("TEST_VAR3", "from env")
+	res, err = New().ResolveKey("TEST_VAR3", "test")
+	require.NoError(t, err)
+	assert.Equal(t, "from env", res.Value)
+	assert.Equal(t, Provenance{Key: "TEST_VAR3", Source: "env"}, res.Provenance)
+
+	_, err = env.ResolveKey(allEnvVars[0], "a/b")
+	require.ErrorIs(t, err, ErrInvalidConfig)
+}
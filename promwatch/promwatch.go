@@ -0,0 +1,90 @@
+// Package promwatch exports metrics of reloads by
+// [github.com/dsh2dsh/expx-dotenv.Loader.Watch] as a Prometheus collector, so
+// operators can alert when a watcher of .env files starts failing silently:
+//
+//	metrics := promwatch.New()
+//	prometheus.MustRegister(metrics)
+//
+//	err := dotenv.New().WithReloadHook(metrics.Observe).Watch(ctx, onChange)
+//
+// It is a separate module, so only its users depend on Prometheus client.
+package promwatch
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+
+	dotenv "github.com/dsh2dsh/expx-dotenv"
+)
+
+// Collector implements [prometheus.Collector] of reloads by
+// [github.com/dsh2dsh/expx-dotenv.Loader.Watch]. Its [Collector.Observe] must
+// be configured as the reload hook of Watch.
+type Collector struct {
+	reloads     prometheus.Counter
+	failures    prometheus.Counter
+	lastReload  prometheus.Gauge
+	lastSuccess prometheus.Gauge
+	changedKeys prometheus.Counter
+}
+
+// New returns new [Collector] of reloads. All its metrics have "dotenv_"
+// prefix.
+func New() *Collector {
+	return &Collector{
+		reloads: prometheus.NewCounter(prometheus.CounterOpts{
+			Name: "dotenv_reloads_total",
+			Help: "Total number of reloads of .env files.",
+		}),
+		failures: prometheus.NewCounter(prometheus.CounterOpts{
+			Name: "dotenv_reload_failures_total",
+			Help: "Total number of failed reloads of .env files.",
+		}),
+		lastReload: prometheus.NewGauge(prometheus.GaugeOpts{
+			Name: "dotenv_last_reload_timestamp_seconds",
+			Help: "Unix time of last reload of .env files.",
+		}),
+		lastSuccess: prometheus.NewGauge(prometheus.GaugeOpts{
+			Name: "dotenv_last_reload_success",
+			Help: "Whether last reload of .env files succeeded.",
+		}),
+		changedKeys: prometheus.NewCounter(prometheus.CounterOpts{
+			Name: "dotenv_changed_keys_total",
+			Help: "Total number of env vars changed by reloads of .env files.",
+		}),
+	}
+}
+
+// Observe records a reload with its changes. It's a reload hook of
+// [github.com/dsh2dsh/expx-dotenv.Loader.WithReloadHook].
+func (self *Collector) Observe(changes dotenv.Changes) {
+	self.reloads.Inc()
+	self.lastReload.SetToCurrentTime()
+	if changes.Err != nil {
+		self.failures.Inc()
+		self.lastSuccess.Set(0)
+		return
+	}
+	self.lastSuccess.Set(1)
+	self.changedKeys.Add(float64(len(changes.Keys)))
+}
+
+// Describe implements [prometheus.Collector].
+func (self *Collector) Describe(ch chan<- *prometheus.Desc) {
+	for _, m := range self.metrics() {
+		m.Describe(ch)
+	}
+}
+
+// Collect implements [prometheus.Collector].
+func (self *Collector) Collect(ch chan<- prometheus.Metric) {
+	for _, m := range self.metrics() {
+		m.Collect(ch)
+	}
+}
+
+func (self *Collector) metrics() []prometheus.Collector {
+	return []prometheus.Collector{
+		self.reloads, self.failures, self.lastReload, self.lastSuccess,
+		self.changedKeys,
+	}
+}
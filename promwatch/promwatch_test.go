@@ -0,0 +1,49 @@
+package promwatch
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	dotenv "github.com/dsh2dsh/expx-dotenv"
+)
+
+func TestCollector(t *testing.T) {
+	c := New()
+	reg := prometheus.NewPedanticRegistry()
+	require.NoError(t, reg.Register(c))
+
+	c.Observe(dotenv.Changes{Keys: []dotenv.KeyDiff{
+		{Key: "TEST_VAR1", Kind: dotenv.DiffChanged, Old: "one", New: "two"},
+		{Key: "TEST_VAR2", Kind: dotenv.DiffAdded, New: "new"},
+	}})
+	c.Observe(dotenv.Changes{})
+
+	require.NoError(t, testutil.GatherAndCompare(reg, strings.NewReader(`
+# HELP dotenv_changed_keys_total Total number of env vars changed by reloads of .env files.
+# TYPE dotenv_changed_keys_total counter
+dotenv_changed_keys_total 2
+# HELP dotenv_last_reload_success Whether last reload of .env files succeeded.
+# TYPE dotenv_last_reload_success gauge
+dotenv_last_reload_success 1
+# HELP dotenv_reload_failures_total Total number of failed reloads of .env files.
+# TYPE dotenv_reload_failures_total counter
+dotenv_reload_failures_total 0
+# HELP dotenv_reloads_total Total number of reloads of .env files.
+# TYPE dotenv_reloads_total counter
+dotenv_reloads_total 2
+`), "dotenv_changed_keys_total", "dotenv_last_reload_success",
+		"dotenv_reload_failures_total", "dotenv_reloads_total"))
+	assert.Positive(t, testutil.ToFloat64(c.lastReload))
+
+	c.Observe(dotenv.Changes{Err: errors.New("broken")})
+	assert.InDelta(t, 3, testutil.ToFloat64(c.reloads), 0)
+	assert.InDelta(t, 1, testutil.ToFloat64(c.failures), 0)
+	assert.InDelta(t, 0, testutil.ToFloat64(c.lastSuccess), 0)
+	assert.InDelta(t, 2, testutil.ToFloat64(c.changedKeys), 0)
+}
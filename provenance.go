@@ -0,0 +1,173 @@
+package dotenv
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"maps"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// keyLineRe matches a line of .env file, which defines a key.
+var keyLineRe = regexp.MustCompile(
+	`^\s*(?:export\s+)?([A-Za-z_][A-Za-z0-9_.-]*)\s*[=:]`)
+
+// Provenance describes where value of an env var comes from. See
+// [Result.ProvenanceJSON].
+type Provenance struct {
+	// Key is a name of env var.
+	Key string `json:"key"`
+
+	// Source is a source of value, like "file:.env" or "env", see
+	// [SourceRef.String].
+	Source string `json:"source"`
+
+	// Line is a line of Source, which defines Key, if Source is a file.
+	Line int `json:"line,omitempty"`
+
+	// Overrode is true if value of Source won over definitions of Key by other
+	// sources.
+	Overrode bool `json:"overrode"`
+
+	// Overridden contains sources, which define Key too, but lost.
+	Overridden []string `json:"overridden,omitempty"`
+
+	fname string
+}
+
+// addProvenance records key defined by source ref, and applied is true if ref
+// defined its value.
+func (self *Loader) addProvenance(ref SourceRef, key string, applied bool) {
+	p, ok := self.provenance[key]
+	switch {
+	case ok:
+		p.Overrode = true
+		p.Overridden = append(p.Overridden, ref.String())
+	case applied:
+		self.provenance[key] = &Provenance{
+			Key:    key,
+			Source: ref.String(),
+			fname:  ref.Name,
+		}
+	default:
+		self.provenance[key] = &Provenance{
+			Key:        key,
+			Source:     SourceRef{Kind: SourceEnv}.String(),
+			Overrode:   true,
+			Overridden: []string{ref.String()},
+		}
+	}
+}
+
+// swapProvenance records ref as source of key, which won over previous source
+// of key, see [Loader.WithConflictResolver].
+func (self *Loader) swapProvenance(ref SourceRef, key string) {
+	p := self.provenance[key]
+	p.Overridden = slices.DeleteFunc(p.Overridden,
+		func(s string) bool { return s == ref.String() })
+	p.Overridden = append(p.Overridden, p.Source)
+	p.Source, p.fname, p.Overrode = ref.String(), ref.Name, true
+}
+
+// provenanceList returns provenance of all env vars, sorted by key.
+func (self *Loader) provenanceList() []Provenance {
+	list := make([]Provenance, 0, len(self.provenance))
+	for _, key := range slices.Sorted(maps.Keys(self.provenance)) {
+		list = append(list, *self.provenance[key])
+	}
+	return list
+}
+
+// ProvenanceJSON returns JSON array of [Provenance] of every env var, defined by
+// loaded .env files, sorted by key. For instance:
+//
+//	[
+//	  {
+//	    "key": "DATABASE_URL",
+//	    "source": "file:.env.local",
+//	    "line": 3,
+//	    "overrode": true,
+//	    "overridden": ["file:.env"]
+//	  }
+//	]
+//
+// It feeds audit tooling of supply chain or configuration. Values of env vars
+// are never included. Line numbers are read from files on call.
+func (self *Result) ProvenanceJSON() ([]byte, error) {
+	lines := make(map[string]map[string]int)
+	list := append(make([]Provenance, 0, len(self.provenance)),
+		self.provenance...)
+	for i := range list {
+		p := &list[i]
+		if p.fname == "" {
+			continue
+		}
+		fileLines, ok := lines[p.fname]
+		if !ok {
+			fileLines = keyLines(self.fsys, p.fname)
+			lines[p.fname] = fileLines
+		}
+		p.Line = fileLines[p.Key]
+	}
+
+	b, err := json.Marshal(list)
+	if err != nil {
+		return nil, fmt.Errorf("marshal provenance: %w", err)
+	}
+	return b, nil
+}
+
+// Source returns name of .env file, which defined effective value of env var
+// key. Empty string means key isn't defined by loaded files or its value comes
+// from the process environment or another source, like [Loader.WithValues].
+// So precedence issues of layered files, like .env.production.local,
+// .env.local, .env.production and .env, can be debugged:
+//
+//	if err := env.Load(); err != nil {
+//		return err
+//	}
+//	log.Printf("DATABASE_URL from %q", env.Result().Source("DATABASE_URL"))
+//
+// See also [Result.ProvenanceJSON].
+func (self *Result) Source(key string) string {
+	i, ok := slices.BinarySearchFunc(self.provenance, key,
+		func(p Provenance, key string) int { return strings.Compare(p.Key, key) })
+	if !ok {
+		return ""
+	}
+	return self.provenance[i].fname
+}
+
+// Sources returns names of .env files, which defined effective values of env
+// vars, by key, see [Result.Source].
+func (self *Result) Sources() map[string]string {
+	sources := make(map[string]string, len(self.provenance))
+	for i := range self.provenance {
+		if p := &self.provenance[i]; p.fname != "" {
+			sources[p.Key] = p.fname
+		}
+	}
+	return sources
+}
+
+// keyLines returns last line of every key, defined by .env file fname of
+// fsys, see [openFile].
+func keyLines(fsys fs.FS, fname string) map[string]int {
+	lines := make(map[string]int)
+	f, err := openFile(fsys, fname)
+	if err != nil {
+		return lines
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for n := 1; scanner.Scan(); n++ {
+		if m := keyLineRe.FindStringSubmatch(scanner.Text()); m != nil {
+			lines[m[1]] = n
+		}
+	}
+	return lines
+}
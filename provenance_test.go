@@ -0,0 +1,70 @@
+package dotenv
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResult_ProvenanceJSON(t *testing.T) {
+	changeDir(t, "testdata/g")
+	restoreEnvVars(t)
+	t.Setenv(allEnvVars[1], "env")
+
+	env := New().WithEnvSuffix("test")
+	require.NoError(t, env.Load())
+
+	b, err := env.Result().ProvenanceJSON()
+	require.NoError(t, err)
+	assert.JSONEq(t, `[
+		{
+			"key": "TEST_VAR1",
+			"source": "file:.env.local",
+			"line": 1,
+			"overrode": true,
+			"overridden": ["file:.env"]
+		},
+		{
+			"key": "TEST_VAR2",
+			"source": "env",
+			"overrode": true,
+			"overridden": ["file:.env.test", "file:.env"]
+		}
+	]`, string(b))
+
+	b, err = (&Result{}).ProvenanceJSON()
+	require.NoError(t, err)
+	assert.JSONEq(t, `[]`, string(b))
+}
+
+func TestResult_Source(t *testing.T) {
+	changeDir(t, "testdata/g")
+	restoreEnvVars(t)
+
+	env := New().WithEnvSuffix("test").WithValues(map[string]string{
+		"TEST_VAR3": "values",
+	})
+	require.NoError(t, env.Load())
+	t.Cleanup(func() { os.Unsetenv("TEST_VAR3") })
+
+	res := env.Result()
+	assert.Equal(t, ".env.local", res.Source(allEnvVars[0]))
+	assert.Equal(t, ".env.test", res.Source(allEnvVars[1]))
+	assert.Empty(t, res.Source("TEST_VAR3"))
+	assert.Empty(t, res.Source("TEST_VAR4"))
+	assert.Equal(t, map[string]string{
+		allEnvVars[0]: ".env.local",
+		allEnvVars[1]: ".env.test",
+	}, res.Sources())
+
+	assert.Empty(t, (&Result{}).Source(allEnvVars[0]))
+	assert.Empty(t, (&Result{}).Sources())
+}
+
+func TestKeyLines(t *testing.T) {
+	assert.Equal(t, map[string]int{"TEST_VAR1": 3, "TEST_VAR2": 2},
+		keyLines(nil, "testdata/g/.env"))
+	assert.Empty(t, keyLines(nil, "testdata/not-exists"))
+}
@@ -0,0 +1,49 @@
+package dotenv
+
+import (
+	"fmt"
+	"io"
+	"time"
+)
+
+// LoadFrom loads .env content from r, like [Loader.Load] loads .env files,
+// instead of looking for them. It's useful when .env content comes from stdin,
+// an HTTP response or a secret manager:
+//
+//	err := dotenv.New().WithOverride().LoadFrom(os.Stdin)
+//
+// Content is applied with the same precedence and override semantics: existing
+// env vars win, unless [Loader.WithOverride] is configured, and
+// [Loader.Precedence] reports it as [SourceReader]. Middlewares, configured by
+// [Loader.WithMiddleware], wrap it too.
+func (self *Loader) LoadFrom(r io.Reader) error {
+	b, err := io.ReadAll(r)
+	if err != nil {
+		return fmt.Errorf("read .env content: %w", err)
+	}
+	return self.loadContent(b)
+}
+
+// LoadString loads .env content s, like [Loader.LoadFrom] does.
+func (self *Loader) LoadString(s string) error {
+	return self.loadContent([]byte(s))
+}
+
+// loadContent loads .env content b, see [Loader.LoadFrom].
+func (self *Loader) loadContent(b []byte) error {
+	return self.runMiddlewares(func() error {
+		self.resetLoad()
+		defer self.finishStats(time.Now())
+		self.startRecording()
+		self.renameEnv()
+
+		ref := SourceRef{Kind: SourceReader}
+		self.precedence = append(self.precedence, ref)
+		vars, err := self.parseBytes(ref.String(), b)
+		if err == nil {
+			err = self.loadVars(ref, vars)
+		}
+		self.traceFile(ref, err)
+		return self.finishLoad(err, nil)
+	})
+}
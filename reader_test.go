@@ -0,0 +1,53 @@
+package dotenv
+
+import (
+	"errors"
+	"os"
+	"strings"
+	"testing"
+	"testing/iotest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoader_LoadFrom(t *testing.T) {
+	restoreEnvVars(t)
+	t.Setenv(allEnvVars[1], "env")
+
+	env := New()
+	require.NoError(t, env.LoadFrom(strings.NewReader(
+		"TEST_VAR1=reader\nTEST_VAR2=reader\n")))
+	assert.Equal(t, "reader", os.Getenv(allEnvVars[0]))
+	assert.Equal(t, "env", os.Getenv(allEnvVars[1]))
+	assert.Equal(t, []SourceRef{{Kind: SourceEnv}, {Kind: SourceReader}},
+		env.Precedence())
+	assert.Equal(t, "reader", SourceReader.String())
+
+	require.NoError(t, New().WithOverride().LoadString("TEST_VAR2=override"))
+	assert.Equal(t, "override", os.Getenv(allEnvVars[1]))
+}
+
+func TestLoader_LoadString_requiredKeys(t *testing.T) {
+	restoreEnvVars(t)
+
+	env := New().WithRequiredKeys(allEnvVars...)
+	require.ErrorIs(t, env.LoadString("TEST_VAR1=1"), ErrMissingKeys)
+	require.NoError(t, env.LoadString("TEST_VAR2=2"))
+}
+
+func TestLoader_LoadFrom_errors(t *testing.T) {
+	restoreEnvVars(t)
+
+	errRead := errors.New("read error")
+	require.ErrorIs(t, New().LoadFrom(iotest.ErrReader(errRead)), errRead)
+	require.Error(t, New().LoadString("TEST_VAR1='unterminated"))
+
+	var called bool
+	env := New().WithMiddleware(func(next LoadFunc) LoadFunc {
+		called = true
+		return next
+	})
+	require.NoError(t, env.LoadString(""))
+	assert.True(t, called)
+}
@@ -0,0 +1,101 @@
+package dotenv
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+)
+
+// ErrUncleanEnv returned by [Loader.ReExecClean] in re-executed process, if its
+// environment contains env vars, which it wouldn't pass.
+var ErrUncleanEnv = errors.New("environment isn't clean")
+
+// reExecEnv is an env var, which marks a process, re-executed by
+// [Loader.ReExecClean].
+const reExecEnv = "DOTENV_REEXEC_CLEAN"
+
+// ReExecClean re-executes current binary with the same arguments and a minimal
+// environment: env vars of allowList and env vars, loaded by last
+// [Loader.Load], with values from [Loader.LookupEnv]. So security-sensitive
+// tools can drop inherited environment early in main:
+//
+//	func main() {
+//		env := dotenv.New()
+//		if err := env.Load(); err != nil {
+//			log.Fatal(err)
+//		}
+//		if err := env.ReExecClean([]string{"HOME", "PATH"}); err != nil {
+//			log.Fatal(err)
+//		}
+//		// The environment is clean here.
+//	}
+//
+// On success it never returns in the original process. In re-executed process
+// it detects its own marker env var, checks the environment contains nothing
+// else, than it would pass, removes the marker and returns nil, so it
+// re-executes once only. The marker can be inherited from a parent process, so
+// it returns an error wrapping [ErrUncleanEnv], if any other env var is present.
+// It returns [ErrNotLoaded], if Load wasn't called yet, and an error wrapping
+// [errors.ErrUnsupported] on platforms without exec, like Windows.
+func (self *Loader) ReExecClean(allowList []string) error {
+	if self.loaded == nil {
+		return ErrNotLoaded
+	} else if _, ok := self.procEnv.LookupEnv(reExecEnv); ok {
+		return self.checkCleanEnviron(allowList)
+	}
+
+	exe, err := os.Executable()
+	if err != nil {
+		return fmt.Errorf("re-exec clean: %w", err)
+	}
+
+	environ := append(self.cleanEnviron(allowList), reExecEnv+"=1")
+	if err := execve(exe, os.Args, environ); err != nil {
+		return fmt.Errorf("re-exec clean %v: %w", exe, err)
+	}
+	return nil
+}
+
+// checkCleanEnviron returns an error, if the process environment contains env
+// vars, except of marker, which [Loader.cleanEnviron] wouldn't return.
+// Otherwise it removes the marker.
+func (self *Loader) checkCleanEnviron(allowList []string) error {
+	var foreign []string
+	for _, s := range self.procEnv.Environ() {
+		k, _, _ := strings.Cut(s, "=")
+		if k == reExecEnv || slices.Contains(allowList, k) {
+			continue
+		} else if _, ok := self.loaded[k]; !ok {
+			foreign = append(foreign, k)
+		}
+	}
+
+	if len(foreign) != 0 {
+		slices.Sort(foreign)
+		return fmt.Errorf("re-exec clean: %w: %v", ErrUncleanEnv,
+			strings.Join(slices.Compact(foreign), ", "))
+	} else if err := self.procEnv.Unsetenv(reExecEnv); err != nil {
+		return fmt.Errorf("re-exec clean: %w", err)
+	}
+	return nil
+}
+
+// cleanEnviron returns env vars of allowList and loaded env vars in the form
+// "key=value", sorted by key.
+func (self *Loader) cleanEnviron(allowList []string) []string {
+	keys := slices.Clone(allowList)
+	for k := range self.loaded {
+		keys = append(keys, k)
+	}
+	slices.Sort(keys)
+
+	environ := make([]string, 0, len(keys))
+	for _, key := range slices.Compact(keys) {
+		if v, ok := self.LookupEnv(key); ok {
+			environ = append(environ, key+"="+v)
+		}
+	}
+	return environ
+}
@@ -0,0 +1,10 @@
+//go:build !unix
+
+package dotenv
+
+import "errors"
+
+// execve isn't supported on this platform.
+var execve = func(argv0 string, argv, envv []string) error {
+	return errors.ErrUnsupported
+}
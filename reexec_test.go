@@ -0,0 +1,78 @@
+package dotenv
+
+import (
+	"errors"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoader_ReExecClean(t *testing.T) {
+	changeDir(t, "testdata/g")
+	restoreEnvVars(t)
+	t.Setenv("TEST_ALLOWED", "allowed")
+	t.Setenv("TEST_DROPPED", "dropped")
+
+	var gotArgv0 string
+	var gotArgv, gotEnvv []string
+	errExec := errors.New("exec")
+	execve0 := execve
+	execve = func(argv0 string, argv, envv []string) error {
+		gotArgv0, gotArgv, gotEnvv = argv0, argv, envv
+		return errExec
+	}
+	t.Cleanup(func() { execve = execve0 })
+
+	env := New()
+	require.ErrorIs(t, env.ReExecClean(nil), ErrNotLoaded)
+	require.NoError(t, env.Load())
+
+	require.ErrorIs(t, env.ReExecClean([]string{"TEST_ALLOWED", "TEST_NONE"}),
+		errExec)
+	assert.Equal(t, valueNoError[string](t)(os.Executable()), gotArgv0)
+	assert.Equal(t, os.Args, gotArgv)
+	assert.Equal(t, []string{
+		"TEST_ALLOWED=allowed",
+		"TEST_VAR1=local",
+		"TEST_VAR2=second",
+		reExecEnv + "=1",
+	}, gotEnvv)
+
+	procEnv := mapProcessEnv{
+		reExecEnv:      "1",
+		"TEST_ALLOWED": "allowed",
+		"TEST_VAR1":    "local",
+	}
+	env = New(WithProcessEnv(procEnv))
+	require.NoError(t, env.Load())
+	gotArgv0 = ""
+	require.NoError(t, env.ReExecClean([]string{"TEST_ALLOWED"}))
+	assert.Empty(t, gotArgv0)
+	assert.NotContains(t, procEnv, reExecEnv)
+}
+
+func TestLoader_ReExecClean_inheritedMarker(t *testing.T) {
+	changeDir(t, "testdata/g")
+	execve0 := execve
+	execve = func(argv0 string, argv, envv []string) error {
+		t.Fatal("unexpected exec")
+		return nil
+	}
+	t.Cleanup(func() { execve = execve0 })
+
+	procEnv := mapProcessEnv{
+		reExecEnv:      "1",
+		"TEST_ALLOWED": "allowed",
+		"TEST_FOREIGN": "foreign",
+		"AWS_SECRET":   "secret",
+	}
+	env := New(WithProcessEnv(procEnv))
+	require.NoError(t, env.Load())
+
+	err := env.ReExecClean([]string{"TEST_ALLOWED"})
+	require.ErrorIs(t, err, ErrUncleanEnv)
+	assert.ErrorContains(t, err, "AWS_SECRET, TEST_FOREIGN")
+	assert.Contains(t, procEnv, reExecEnv)
+}
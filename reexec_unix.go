@@ -0,0 +1,8 @@
+//go:build unix
+
+package dotenv
+
+import "syscall"
+
+// execve replaces current process, see [syscall.Exec].
+var execve = syscall.Exec
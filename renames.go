@@ -0,0 +1,83 @@
+package dotenv
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+)
+
+// ErrRenamedKey is a warning of [Loader.Load], if a .env file defines a key,
+// renamed by [Loader.WithRenames].
+var ErrRenamedKey = errors.New("renamed key")
+
+// WithRenames configures [Loader.Load] to rename keys of .env files from old
+// names to new ones, using renames map, which maps old names to new names. For
+// instance:
+//
+//	env := dotenv.New().WithRenames(map[string]string{
+//		"DB_URL": "DATABASE_URL",
+//	})
+//
+// loads DB_URL of .env files as DATABASE_URL. If a .env file defines both
+// names, value of the new name wins. Every usage of an old name adds a warning
+// wrapping [ErrRenamedKey], see [Result.Warnings] and
+// [Loader.WithWarningHook]. So renames can be rolled out across many
+// deployments smoothly.
+//
+// Old names of the process environment are renamed too: if it defines an old
+// name, but not the new one, the new name is set to the same value, with a
+// warning. The old name itself stays as is.
+//
+// By default old names of .env files aren't set, see [Loader.WithKeepRenamed].
+func (self *Loader) WithRenames(renames map[string]string) *Loader {
+	self.renames = renames
+	return self
+}
+
+// WithKeepRenamed configures [Loader.Load] to set old names too, together with
+// new ones, see [Loader.WithRenames].
+func (self *Loader) WithKeepRenamed() *Loader {
+	self.keepRenamed = true
+	return self
+}
+
+// renameKeys renames keys of vars, loaded from fname, according to configured
+// renames.
+func (self *Loader) renameKeys(fname string, vars map[string]string) {
+	for _, oldName := range slices.Sorted(maps.Keys(self.renames)) {
+		v, ok := vars[oldName]
+		if !ok {
+			continue
+		}
+
+		newName := self.renames[oldName]
+		self.warnings = append(self.warnings, fmt.Errorf(
+			"%w: %v from %v, use %v", ErrRenamedKey, oldName, fname, newName))
+		if _, ok := vars[newName]; !ok {
+			vars[newName] = v
+		}
+		if !self.keepRenamed {
+			delete(vars, oldName)
+		}
+	}
+}
+
+// renameEnv sets new names of env vars, defined by the process environment
+// with old names only, according to configured renames.
+func (self *Loader) renameEnv() {
+	for _, oldName := range slices.Sorted(maps.Keys(self.renames)) {
+		v, ok := self.lookupPending(oldName)
+		if !ok {
+			continue
+		}
+
+		newName := self.renames[oldName]
+		source := SourceRef{Kind: SourceEnv}.String()
+		self.warnings = append(self.warnings, fmt.Errorf(
+			"%w: %v from %v, use %v", ErrRenamedKey, oldName, source, newName))
+		if _, ok := self.lookupPending(newName); !ok {
+			self.setenv(source, newName, v)
+		}
+	}
+}
@@ -0,0 +1,70 @@
+package dotenv
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoader_WithRenames(t *testing.T) {
+	changeDir(t, "testdata/h")
+	restoreEnvVars(t)
+	t.Setenv("DATABSE_URL", "")
+	require.NoError(t, os.Unsetenv("DATABSE_URL"))
+	t.Setenv("DATABASE_URL", "")
+	require.NoError(t, os.Unsetenv("DATABASE_URL"))
+
+	env := New()
+	assert.Same(t, env, env.WithRenames(map[string]string{
+		"DATABSE_URL": "DATABASE_URL",
+		"NOT_EXISTS":  "NEW_NOT_EXISTS",
+	}))
+	require.NoError(t, env.Load())
+
+	_, ok := os.LookupEnv("DATABSE_URL")
+	assert.False(t, ok)
+	assert.NotEmpty(t, os.Getenv("DATABASE_URL"))
+	warnings := env.Result().Warnings
+	require.Len(t, warnings, 1)
+	require.ErrorIs(t, warnings[0], ErrRenamedKey)
+	assert.Equal(t, "renamed key: DATABSE_URL from .env, use DATABASE_URL",
+		warnings[0].Error())
+
+	require.NoError(t, os.Unsetenv("DATABASE_URL"))
+	assert.Same(t, env, env.WithKeepRenamed())
+	require.NoError(t, env.Load())
+	assert.Equal(t, os.Getenv("DATABSE_URL"), os.Getenv("DATABASE_URL"))
+}
+
+func TestLoader_WithRenames_env(t *testing.T) {
+	changeDir(t, "testdata/h")
+	restoreEnvVars(t)
+	t.Setenv("OLD_NAME", "from env")
+	t.Setenv("NEW_NAME", "")
+	require.NoError(t, os.Unsetenv("NEW_NAME"))
+
+	env := New().WithRenames(map[string]string{"OLD_NAME": "NEW_NAME"})
+	require.NoError(t, env.Load())
+	assert.Equal(t, "from env", os.Getenv("NEW_NAME"))
+	assert.Equal(t, "from env", os.Getenv("OLD_NAME"))
+
+	warnings := env.Result().Warnings
+	require.Len(t, warnings, 1)
+	require.ErrorIs(t, warnings[0], ErrRenamedKey)
+	assert.Equal(t, "renamed key: OLD_NAME from env, use NEW_NAME",
+		warnings[0].Error())
+
+	t.Setenv("NEW_NAME", "new")
+	require.NoError(t, env.Load())
+	assert.Equal(t, "new", os.Getenv("NEW_NAME"))
+	assert.Len(t, env.Result().Warnings, 1)
+}
+
+func TestLoader_renameKeys(t *testing.T) {
+	env := New().WithRenames(map[string]string{"OLD": "NEW"})
+	vars := map[string]string{"OLD": "old", "NEW": "new"}
+	env.renameKeys(".env", vars)
+	assert.Equal(t, map[string]string{"NEW": "new"}, vars)
+}
@@ -0,0 +1,239 @@
+package dotenv
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+)
+
+// Recording is a record of lookup of [Loader.Load]: answers of filesystem,
+// hashes of loaded .env files and where lookup stopped. See
+// [Loader.WithRecording]. It can be marshaled into JSON, attached to a bug
+// report and replayed by [Loader.ReplayLoad].
+type Recording struct {
+	// Dir is absolute path of dir, where lookup started.
+	Dir string `json:"dir"`
+
+	// Stats contains answers of [Filer.Stat] in order of calls.
+	Stats []RecordedStat `json:"stats"`
+
+	// Dirs contains read dirs, like dirs matched against glob patterns of
+	// [Loader.WithRootFiles].
+	Dirs []RecordedDir `json:"dirs,omitempty"`
+
+	// Files contains loaded .env files.
+	Files []RecordedFile `json:"files,omitempty"`
+
+	// Stop describes where and why lookup stopped.
+	Stop Stop `json:"stop"`
+}
+
+// RecordedStat is an answer of [Filer.Stat], see [Recording].
+type RecordedStat struct {
+	// Name is absolute path of file.
+	Name string `json:"name"`
+
+	// Exists is true if Stat succeeded.
+	Exists bool `json:"exists"`
+
+	// Error is an error of Stat, besides of [fs.ErrNotExist].
+	Error string `json:"error,omitempty"`
+
+	// Permission is true if Error is [fs.ErrPermission].
+	Permission bool `json:"permission,omitempty"`
+}
+
+// RecordedDir is a read dir, see [Recording].
+type RecordedDir struct {
+	// Name is absolute path of dir.
+	Name string `json:"name"`
+
+	// Entries contains names of entries of dir, sorted by name.
+	Entries []string `json:"entries"`
+}
+
+// RecordedFile is a loaded .env file, see [Recording]. Only hash of its
+// content is recorded, so recordings don't leak values.
+type RecordedFile struct {
+	// Name is a name of file, like [Loader.Load] loaded it.
+	Name string `json:"name"`
+
+	// SHA256 is hex encoded sha256 checksum of content of file.
+	SHA256 string `json:"sha256"`
+}
+
+// Replay is a result of [Loader.ReplayLoad].
+type Replay struct {
+	// Files contains .env files, which would be loaded, in cascade order.
+	Files []string
+
+	// Stop describes where and why replayed lookup stopped.
+	Stop Stop
+
+	// Warnings contains problems of replayed lookup, like [Result.Warnings].
+	Warnings []error
+}
+
+// WithRecording configures [Loader.Load] to record its lookup, see
+// [Loader.Recording]. So maintainers can reproduce discovery bugs, reported by
+// users, from a single attached file:
+//
+//	env := dotenv.New().WithRecording()
+//	err := env.Load()
+//	b, _ := json.Marshal(env.Recording())
+//	os.WriteFile("dotenv-recording.json", b, 0o600)
+func (self *Loader) WithRecording() *Loader {
+	self.record = true
+	return self
+}
+
+// Recording returns a record of last [Loader.Load], if it's configured by
+// [Loader.WithRecording], or nil.
+func (self *Loader) Recording() *Recording { return self.recording }
+
+// startRecording starts a new recording, if it's configured.
+func (self *Loader) startRecording() {
+	if !self.record {
+		self.recording = nil
+		return
+	}
+
+	dir, err := os.Getwd()
+	if err != nil {
+		dir = self.fallbackStartDir
+	}
+	self.recording = &Recording{Dir: dir}
+}
+
+// recordStat records answer err of [Filer.Stat] for fname.
+func (self *Loader) recordStat(fname string, err error) {
+	rec := self.recording
+	if rec == nil {
+		return
+	} else if !filepath.IsAbs(fname) {
+		fname = filepath.Join(rec.Dir, fname)
+	}
+
+	stat := RecordedStat{Name: fname, Exists: err == nil}
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		stat.Error = err.Error()
+		stat.Permission = errors.Is(err, fs.ErrPermission)
+	}
+	rec.Stats = append(rec.Stats, stat)
+}
+
+// recordDir records names of entries of dir, if it was read successfully.
+func (self *Loader) recordDir(dir string, entries []fs.DirEntry, err error) {
+	rec := self.recording
+	if rec == nil || err != nil {
+		return
+	} else if !filepath.IsAbs(dir) {
+		dir = filepath.Join(rec.Dir, dir)
+	}
+
+	names := make([]string, len(entries))
+	for i, entry := range entries {
+		names[i] = entry.Name()
+	}
+	rec.Dirs = append(rec.Dirs, RecordedDir{Name: dir, Entries: names})
+}
+
+// recordFile records hash of content b of loaded file fname.
+func (self *Loader) recordFile(fname string, b []byte) {
+	if self.recording == nil {
+		return
+	}
+	sum := sha256.Sum256(b)
+	self.recording.Files = append(self.recording.Files,
+		RecordedFile{Name: fname, SHA256: hex.EncodeToString(sum[:])})
+}
+
+// ReplayLoad replays lookup of rec, recorded by [Loader.WithRecording],
+// against configuration of this [Loader], instead of real filesystem. It
+// starts at rec.Dir and every [Filer.Stat] and read of dir gets recorded
+// answer, or [fs.ErrNotExist], if it wasn't recorded. It returns .env files, which would
+// be loaded, and where lookup stopped, but doesn't read or apply them.
+//
+// Some parts of lookup, like .envrc files of [Loader.WithEnvrc] and
+// [Loader.WithRootCallback], aren't recorded and use real filesystem or
+// configured callback.
+func (self *Loader) ReplayLoad(rec *Recording) (*Replay, error) {
+	filer, startAt := self.filer, self.startAt
+	defer func() {
+		self.filer, self.startAt, self.dirCache = filer, startAt, nil
+	}()
+	self.filer, self.startAt = newReplayFiler(rec), rec.Dir
+	self.stats, self.stop, self.warnings, self.skipped = Stats{}, Stop{}, nil, nil
+	self.dirCache = nil
+
+	if err := self.Validate(); err != nil {
+		return nil, err
+	}
+
+	envs, err := self.lookupEnvFiles()
+	if err != nil {
+		return nil, err
+	}
+	return &Replay{Files: envs, Stop: self.stop, Warnings: self.warnings}, nil
+}
+
+// replayFiler implements [Filer] and [dirReader] using answers of a
+// [Recording].
+type replayFiler struct {
+	stats map[string]RecordedStat
+	dirs  map[string][]string
+}
+
+func newReplayFiler(rec *Recording) replayFiler {
+	answers := replayFiler{
+		stats: make(map[string]RecordedStat, len(rec.Stats)),
+		dirs:  make(map[string][]string, len(rec.Dirs)),
+	}
+	for _, stat := range rec.Stats {
+		answers.stats[stat.Name] = stat
+	}
+	for _, dir := range rec.Dirs {
+		answers.dirs[dir.Name] = dir.Entries
+	}
+	return answers
+}
+
+func (self replayFiler) Stat(name string) (os.FileInfo, error) {
+	stat, ok := self.stats[name]
+	switch {
+	case !ok:
+		return nil, &fs.PathError{Op: "stat", Path: name, Err: fs.ErrNotExist}
+	case stat.Permission:
+		return nil, &fs.PathError{Op: "stat", Path: name, Err: fs.ErrPermission}
+	case stat.Error != "":
+		return nil, &fs.PathError{Op: "stat", Path: name, Err: errors.New(stat.Error)}
+	case !stat.Exists:
+		return nil, &fs.PathError{Op: "stat", Path: name, Err: fs.ErrNotExist}
+	}
+	return nil, nil //nolint:nilnil // callers check existence only
+}
+
+func (self replayFiler) ReadDir(name string) ([]fs.DirEntry, error) {
+	names, ok := self.dirs[name]
+	if !ok {
+		return nil, &fs.PathError{Op: "readdir", Path: name, Err: fs.ErrNotExist}
+	}
+
+	entries := make([]fs.DirEntry, len(names))
+	for i, name := range names {
+		entries[i] = replayDirEntry(name)
+	}
+	return entries, nil
+}
+
+// replayDirEntry implements [fs.DirEntry] of a recorded dir, which knows name
+// of entry only.
+type replayDirEntry string
+
+func (self replayDirEntry) Name() string               { return string(self) }
+func (self replayDirEntry) IsDir() bool                { return false }
+func (self replayDirEntry) Type() fs.FileMode          { return 0 }
+func (self replayDirEntry) Info() (fs.FileInfo, error) { return nil, fs.ErrNotExist }
@@ -0,0 +1,117 @@
+package dotenv
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoader_WithRecording(t *testing.T) {
+	changeDir(t, "testdata/j/k")
+	restoreEnvVars(t)
+	wd, err := os.Getwd()
+	require.NoError(t, err)
+	envDir := filepath.Dir(wd)
+
+	env := New()
+	assert.Nil(t, env.Recording())
+	assert.Same(t, env, env.WithRecording())
+	require.NoError(t, env.Load())
+
+	rec := env.Recording()
+	require.NotNil(t, rec)
+	assert.Equal(t, wd, rec.Dir)
+	assert.Equal(t, Stop{Reason: StopFound, Dir: envDir}, rec.Stop)
+	assert.Contains(t, rec.Stats, RecordedStat{
+		Name: filepath.Join(wd, ".env.local"),
+	})
+	assert.Contains(t, rec.Stats, RecordedStat{
+		Name: filepath.Join(envDir, ".env"), Exists: true,
+	})
+
+	b, err := os.ReadFile(filepath.Join(envDir, ".env"))
+	require.NoError(t, err)
+	sum := sha256.Sum256(b)
+	assert.Equal(t, []RecordedFile{{
+		Name:   filepath.Join(envDir, ".env"),
+		SHA256: hex.EncodeToString(sum[:]),
+	}}, rec.Files)
+
+	b, err = json.Marshal(rec)
+	require.NoError(t, err)
+
+	changeDir(t, t.TempDir())
+	var replayed Recording
+	require.NoError(t, json.Unmarshal(b, &replayed))
+	replay, err := New().ReplayLoad(&replayed)
+	require.NoError(t, err)
+	assert.Equal(t, []string{filepath.Join(envDir, ".env")}, replay.Files)
+	assert.Equal(t, rec.Stop, replay.Stop)
+
+	replay, err = New().WithDepth(1).ReplayLoad(&replayed)
+	require.NoError(t, err)
+	assert.Empty(t, replay.Files)
+	assert.Equal(t, StopDepth, replay.Stop.Reason)
+}
+
+func TestLoader_ReplayLoad_permission(t *testing.T) {
+	rec := &Recording{
+		Dir: "/a/b",
+		Stats: []RecordedStat{
+			{Name: "/a/b/go.mod"},
+			{Name: "/a/go.mod", Error: "stat /a/go.mod: permission denied", Permission: true},
+		},
+	}
+
+	_, err := New().ReplayLoad(rec)
+	require.ErrorIs(t, err, os.ErrPermission)
+
+	replay, err := New().WithIgnorePermissionErrors().ReplayLoad(rec)
+	require.NoError(t, err)
+	assert.Equal(t, Stop{Reason: StopPermission, Dir: "/a"}, replay.Stop)
+	assert.Len(t, replay.Warnings, 1)
+
+	rec.Stats[1] = RecordedStat{Name: "/a/go.mod", Error: "stat /a/go.mod: i/o error"}
+	_, err = New().ReplayLoad(rec)
+	require.ErrorContains(t, err, "i/o error")
+}
+
+func TestLoader_ReplayLoad_rootGlob(t *testing.T) {
+	dir := valueNoError[string](t)(filepath.EvalSymlinks(t.TempDir()))
+	subDir := filepath.Join(dir, "sub")
+	require.NoError(t, os.MkdirAll(subDir, 0o700))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "app.workspace"), nil,
+		0o600))
+	changeDir(t, subDir)
+	restoreEnvVars(t)
+
+	env := New().WithoutSetenv().WithRootFiles("*.workspace").WithRecording()
+	require.NoError(t, env.Load())
+	rec := env.Recording()
+	assert.Contains(t, rec.Dirs, RecordedDir{
+		Name: dir, Entries: []string{"app.workspace", "sub"},
+	})
+
+	require.NoError(t, os.Remove(filepath.Join(dir, "app.workspace")))
+	replay, err := env.ReplayLoad(rec)
+	require.NoError(t, err)
+	assert.Equal(t, rec.Stop, replay.Stop)
+	assert.Equal(t, Stop{
+		Reason: StopRootFile, Dir: dir, RootFile: "app.workspace",
+	}, replay.Stop)
+}
+
+func TestLoader_ReplayLoad_restores(t *testing.T) {
+	env := New().WithRootDir("/a")
+	filer := env.filer
+	_, err := env.ReplayLoad(&Recording{Dir: "/b"})
+	require.ErrorIs(t, err, ErrRootNotAncestor)
+	assert.Equal(t, filer, env.filer)
+	assert.Empty(t, env.startAt)
+}
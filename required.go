@@ -0,0 +1,158 @@
+package dotenv
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"maps"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// ErrMissingKeys returned by [Loader.Load] if any of keys, configured by
+// [Loader.WithRequiredKeys], isn't defined.
+var ErrMissingKeys = errors.New("missing required keys")
+
+// ErrMissingFiles returned by [Loader.Load] if any of .env files, configured by
+// [Loader.WithRequiredFiles], doesn't exist.
+var ErrMissingFiles = errors.New("missing required files")
+
+// ErrNoEnvFiles returned by [Loader.Load] if lookup found no .env files and
+// it's configured by [Loader.WithRequired].
+var ErrNoEnvFiles = errors.New("no .env files found")
+
+// maxSuggestDistance is max Levenshtein distance between a missing key and a
+// key, suggested instead of it.
+const maxSuggestDistance = 2
+
+// WithRequiredKeys configures [Loader.Load] to return an error wrapping
+// [ErrMissingKeys], if any of keys isn't defined by .env files or the
+// environment after loading. If .env files define a similarly spelled key,
+// the error includes a hint like:
+//
+//	DATABASE_URL (did you mean DATABSE_URL from .env.local:12?)
+func (self *Loader) WithRequiredKeys(keys ...string) *Loader {
+	self.required = keys
+	return self
+}
+
+// WithRequiredFiles configures [Loader.Load] to return an error wrapping
+// [ErrMissingFiles], if any of .env files named fnames doesn't exist in the dir
+// with .env files, or no such dir found. Other .env files of the cascade stay
+// optional. So misdeployed images without their base .env file fail fast:
+//
+//	env := dotenv.New().WithRequiredFiles(".env")
+func (self *Loader) WithRequiredFiles(fnames ...string) *Loader {
+	self.requiredFiles = fnames
+	return self
+}
+
+// WithRequired configures [Loader.Load] to return an error wrapping
+// [ErrNoEnvFiles], if lookup finished without finding any .env file. So a typo
+// in name of file or environment doesn't silently result in an app running
+// with empty config:
+//
+//	env := dotenv.New().WithEnvSuffix("production").WithRequired()
+//
+// See also [Loader.WithRequiredFiles].
+func (self *Loader) WithRequired() *Loader {
+	self.mustFind = true
+	return self
+}
+
+// checkRequiredFiles returns an error if envs is empty and it's configured by
+// [Loader.WithRequired], or any of required files isn't in envs.
+func (self *Loader) checkRequiredFiles(envs []string) error {
+	if self.mustFind && len(envs) == 0 {
+		dir := self.stop.Dir
+		if dir == "" {
+			dir = "."
+		}
+		return fmt.Errorf("%w: looked for %v, stopped at %v by %v", ErrNoEnvFiles,
+			self.envFiles(), dir, self.stop.Reason)
+	}
+
+	var missing []string
+	for _, fname := range self.requiredFiles {
+		if !slices.ContainsFunc(envs, func(s string) bool {
+			return filepath.Base(s) == fname
+		}) {
+			missing = append(missing, fname)
+		}
+	}
+
+	if len(missing) == 0 {
+		return nil
+	}
+	return fmt.Errorf("%w: %v", ErrMissingFiles, strings.Join(missing, ", "))
+}
+
+// checkRequired returns an error if any of required keys isn't defined.
+func (self *Loader) checkRequired() error {
+	var missing []string
+	for _, key := range self.required {
+		if _, ok := self.LookupEnv(key); !ok {
+			missing = append(missing, key+self.suggestKey(key))
+		}
+	}
+
+	if len(missing) == 0 {
+		return nil
+	}
+	return fmt.Errorf("%w: %v", ErrMissingKeys, strings.Join(missing, ", "))
+}
+
+// suggestKey returns a hint about key, defined by .env files, which is the
+// nearest to key, or empty string.
+func (self *Loader) suggestKey(key string) string {
+	bestKey, bestDist := "", maxSuggestDistance+1
+	for _, k := range slices.Sorted(maps.Keys(self.sources)) {
+		if d := levenshtein(key, k); d < bestDist {
+			bestKey, bestDist = k, d
+		}
+	}
+
+	if bestKey == "" {
+		return ""
+	}
+
+	fname := self.sources[bestKey]
+	isFile := slices.ContainsFunc(self.precedence, func(ref SourceRef) bool {
+		return ref.isFile() && ref.Name == fname
+	})
+	if !isFile {
+		return fmt.Sprintf(" (did you mean %v from %v?)", bestKey, fname)
+	} else if line := keyLine(self.fsys, fname, bestKey); line > 0 {
+		return fmt.Sprintf(" (did you mean %v from %v:%v?)", bestKey, fname, line)
+	}
+	return fmt.Sprintf(" (did you mean %v from %v?)", bestKey, fname)
+}
+
+// keyLine returns number of the last line in file fname of fsys, see
+// [openFile], which defines key, or 0. The last one, because it wins.
+func keyLine(fsys fs.FS, fname, key string) int {
+	return keyLines(fsys, fname)[key]
+}
+
+// levenshtein returns Levenshtein distance between a and b.
+func levenshtein(a, b string) int {
+	prev := make([]int, len(b)+1)
+	cur := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+
+	for i := 1; i <= len(a); i++ {
+		cur[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev, cur = cur, prev
+	}
+	return prev[len(b)]
+}
@@ -0,0 +1,133 @@
+package dotenv
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoader_WithRequiredKeys(t *testing.T) {
+	changeDir(t, "testdata/h")
+	restoreEnvVars(t)
+	t.Setenv("DATABSE_URL", "")
+
+	env := New()
+	assert.Same(t, env, env.WithRequiredKeys("TEST_VAR1", "DATABASE_URL",
+		"COMPLETELY_DIFFERENT"))
+	err := env.Load()
+	require.ErrorIs(t, err, ErrMissingKeys)
+	assert.Equal(t, "missing required keys: "+
+		"DATABASE_URL (did you mean DATABSE_URL from .env:2?), "+
+		"COMPLETELY_DIFFERENT", err.Error())
+
+	t.Setenv("DATABASE_URL", "postgres://localhost/db")
+	t.Setenv("COMPLETELY_DIFFERENT", "")
+	require.NoError(t, env.Load())
+}
+
+func TestLoader_WithRequiredKeys_values(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "values"),
+		[]byte("DATABSE_URL=file\n"), 0o600))
+	changeDir(t, dir)
+	restoreEnvVars(t)
+	t.Setenv("DATABSE_URL", "")
+
+	err := New().WithRootDir(".").WithRequiredKeys("DATABASE_URL").
+		WithValues(map[string]string{"DATABSE_URL": "values"}).Load()
+	require.ErrorIs(t, err, ErrMissingKeys)
+	assert.Equal(t, "missing required keys: "+
+		"DATABASE_URL (did you mean DATABSE_URL from values?)", err.Error())
+}
+
+func TestLoader_WithRequiredFiles(t *testing.T) {
+	tests := []struct {
+		name    string
+		dir     string
+		fnames  []string
+		before  func(env *Loader)
+		wantErr string
+	}{
+		{
+			name:   "found",
+			dir:    "testdata/g",
+			fnames: []string{".env", ".env.local"},
+		},
+		{
+			name:    "missing",
+			dir:     "testdata/g",
+			fnames:  []string{".env", ".env.production", ".env.production.local"},
+			wantErr: "missing required files: .env.production, .env.production.local",
+		},
+		{
+			name:    "nothing found",
+			dir:     "testdata/a",
+			fnames:  []string{".env"},
+			before:  func(env *Loader) { env.WithDepth(1) },
+			wantErr: "missing required files: .env",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			changeDir(t, tt.dir)
+			restoreEnvVars(t)
+			env := New()
+			assert.Same(t, env, env.WithRequiredFiles(tt.fnames...))
+			if tt.before != nil {
+				tt.before(env)
+			}
+
+			err := env.Load()
+			if tt.wantErr == "" {
+				require.NoError(t, err)
+				return
+			}
+			require.ErrorIs(t, err, ErrMissingFiles)
+			assert.Equal(t, tt.wantErr, err.Error())
+		})
+	}
+}
+
+func TestLoader_WithRequired(t *testing.T) {
+	restoreEnvVars(t)
+	changeDir(t, "testdata/a")
+
+	env := New()
+	assert.Same(t, env, env.WithRequired())
+	require.NoError(t, env.Load())
+	assert.Equal(t, "testdata", os.Getenv(allEnvVars[0]))
+
+	err := env.WithDepth(1).Load()
+	require.ErrorIs(t, err, ErrNoEnvFiles)
+	assert.Equal(t,
+		"no .env files found: looked for [.env.local .env], stopped at . by depth",
+		err.Error())
+}
+
+func TestKeyLine(t *testing.T) {
+	assert.Equal(t, 2, keyLine(nil, "testdata/h/.env", "DATABSE_URL"))
+	assert.Equal(t, 0, keyLine(nil, "testdata/h/.env", "DATABSE"))
+	assert.Equal(t, 0, keyLine(nil, "testdata/h/not-exists", "DATABSE_URL"))
+}
+
+func TestLevenshtein(t *testing.T) {
+	tests := []struct {
+		a, b   string
+		expect int
+	}{
+		{a: "", b: "", expect: 0},
+		{a: "abc", b: "", expect: 3},
+		{a: "", b: "abc", expect: 3},
+		{a: "DATABASE_URL", b: "DATABSE_URL", expect: 1},
+		{a: "DATABASE_URL", b: "DATABASE_URI", expect: 1},
+		{a: "kitten", b: "sitting", expect: 3},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.expect, levenshtein(tt.a, tt.b), "%v %v", tt.a, tt.b)
+	}
+}
@@ -0,0 +1,47 @@
+package dotenv
+
+// Resolve looks for .env files like [Loader.Load] does and returns them in the
+// order Load would load them, without reading any of them and without changing
+// the environment. The first file has the highest precedence. It's useful for
+// diagnostics, like a "--show-config" flag:
+//
+//	files, err := dotenv.New().WithEnvSuffix("dev").Resolve()
+//	if err != nil {
+//		return err
+//	}
+//	for _, fname := range files {
+//		fmt.Println(fname)
+//	}
+//
+// It uses a copy of this [Loader] configuration, so [Loader.Result] doesn't
+// change. Like Load, it returns an error, if configuration isn't valid or any
+// of files, configured by [Loader.WithRequiredFiles], doesn't exist.
+func (self *Loader) Resolve() ([]string, error) {
+	l := *self
+	l.vars, l.lastDir = nil, ""
+	l.result, l.recording, l.trace = nil, nil, nil
+	l.resetLoad()
+
+	if err := l.Validate(); err != nil {
+		return nil, err
+	}
+
+	envs, err := l.lookupEnvFiles()
+	if err != nil {
+		return nil, err
+	} else if err := l.checkRequiredFiles(envs); err != nil {
+		return nil, err
+	}
+
+	refs := make([]SourceRef, 0, len(envs))
+	for _, fname := range l.uniqueFiles(envs) {
+		refs = append(refs, SourceRef{Kind: SourceFile, Name: fname})
+	}
+	l.sortByPriority(refs)
+
+	files := make([]string, len(refs))
+	for i, ref := range refs {
+		files[i] = ref.Name
+	}
+	return files, nil
+}
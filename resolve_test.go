@@ -0,0 +1,57 @@
+package dotenv
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoader_Resolve(t *testing.T) {
+	changeDir(t, filepath.Join("testdata", "g"))
+	restoreEnvVars(t)
+
+	tests := []struct {
+		name   string
+		env    *Loader
+		expect []string
+	}{
+		{
+			name:   "default",
+			env:    New(),
+			expect: []string{".env.local", ".env"},
+		},
+		{
+			name:   "WithEnvSuffix",
+			env:    New().WithEnvSuffix("test"),
+			expect: []string{".env.local", ".env.test", ".env"},
+		},
+		{
+			name:   "WithSourcePriority",
+			env:    New().WithSourcePriority(".env", 10),
+			expect: []string{".env", ".env.local"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			files, err := tt.env.Resolve()
+			require.NoError(t, err)
+			assert.Equal(t, tt.expect, files)
+			assert.Nil(t, tt.env.Result())
+			assert.Empty(t, os.Getenv(allEnvVars[0]))
+		})
+	}
+}
+
+func TestLoader_Resolve_errors(t *testing.T) {
+	changeDir(t, filepath.Join("testdata", "g"))
+
+	_, err := New().WithEnvSuffix("a/b").Resolve()
+	require.ErrorIs(t, err, ErrInvalidConfig)
+
+	_, err = New().WithRequiredFiles(".env.production").Resolve()
+	require.Error(t, err)
+}
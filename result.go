@@ -0,0 +1,206 @@
+package dotenv
+
+import (
+	"io/fs"
+	"maps"
+	"slices"
+	"strconv"
+	"time"
+)
+
+// Result describes what happened during last [Loader.Load]. See
+// [Loader.Result].
+type Result struct {
+	// Stats contains timing and statistics of Load.
+	Stats Stats
+
+	// Stop describes why lookup of .env files stopped.
+	Stop Stop
+
+	// Warnings contains problems, which didn't fail Load.
+	Warnings []error
+
+	// Skipped contains .env files, which exist, but were skipped.
+	Skipped []SkippedFile
+
+	// Dir is a dir, where .env files were found. Empty string means current
+	// dir or, if Files is empty, nothing was found.
+	Dir string
+
+	// Files contains loaded .env files in order of loading, so the first file
+	// has the highest precedence.
+	Files []string
+
+	// SetKeys contains env vars, which were set by loaded sources, sorted by
+	// key.
+	SetKeys []string
+
+	// ExistingKeys contains env vars, which loaded sources define, but which
+	// were skipped, because they already existed in the environment, sorted
+	// by key.
+	ExistingKeys []string
+
+	provenance []Provenance
+	fsys       fs.FS
+}
+
+// SkippedFile describes a .env file, which exists, but [Loader.Load] skipped it,
+// like:
+//
+//   - Its dir is ignored by ignore file, see [Loader.WithIgnoreFile]. Only the
+//     first found file of the dir is reported.
+//   - It's the same physical file as another loaded .env file.
+//   - It can't be loaded and Load continued, see [Loader.WithContinueOnError].
+type SkippedFile struct {
+	// Name is a name of .env file.
+	Name string
+
+	// Reason is a human readable reason, why it was skipped.
+	Reason string
+}
+
+// LoadResult loads .env files like [Loader.Load] does and returns its result,
+// see [Result], together with error of Load. So callers can tell users what
+// actually happened:
+//
+//	res, err := dotenv.New().LoadResult()
+//	if err != nil {
+//		return err
+//	}
+//	log.Printf("loaded %v from %q in %v, set %v, kept %v", res.Files, res.Dir,
+//		res.Stats.WallTime, res.SetKeys, res.ExistingKeys)
+//
+// Result is nil, if Load failed before lookup, like a middleware did.
+func (self *Loader) LoadResult(callbacks ...func() error) (*Result, error) {
+	self.result = nil
+	err := self.Load(callbacks...)
+	return self.result, err
+}
+
+// resultKeys returns env vars, set by loaded sources, and env vars, which
+// already existed, both sorted by key.
+func (self *Loader) resultKeys() (setKeys, existingKeys []string) {
+	for _, k := range slices.Sorted(maps.Keys(self.winners)) {
+		if self.winners[k].Kind == SourceEnv {
+			existingKeys = append(existingKeys, k)
+		} else {
+			setKeys = append(setKeys, k)
+		}
+	}
+	return setKeys, existingKeys
+}
+
+// resultFiles returns loaded .env files in order of loading.
+func (self *Loader) resultFiles() []string {
+	var files []string
+	for _, ref := range self.precedence {
+		if ref.Kind == SourceFile || ref.Kind == SourceSnapshot {
+			files = append(files, ref.Name)
+		}
+	}
+	return files
+}
+
+// skipFile records fname as skipped by reason.
+func (self *Loader) skipFile(fname, reason string) {
+	self.skipped = append(self.skipped, SkippedFile{Name: fname, Reason: reason})
+}
+
+// StopReason is a reason why lookup of .env files stopped.
+type StopReason int
+
+const (
+	// StopNone means lookup didn't stop by any reason below, probably because of
+	// an error.
+	StopNone StopReason = iota
+
+	// StopFound means .env files were found.
+	StopFound
+
+	// StopDepth means lookup reached depth, configured by [Loader.WithDepth].
+	StopDepth
+
+	// StopRootDir means lookup reached root dir, configured by
+	// [Loader.WithRootDir].
+	StopRootDir
+
+	// StopRootFile means lookup reached a dir with any of files, configured by
+	// [Loader.WithRootFiles].
+	StopRootFile
+
+	// StopCallback means callback, configured by [Loader.WithRootCallback],
+	// returned true.
+	StopCallback
+
+	// StopPermission means lookup got [os.ErrPermission] and stopped, because
+	// it's configured by [Loader.WithIgnorePermissionErrors].
+	StopPermission
+
+	// StopVanished means found dir with .env files disappeared during lookup,
+	// even after retry.
+	StopVanished
+)
+
+var stopReasonNames = [...]string{
+	StopNone:       "none",
+	StopFound:      "found",
+	StopDepth:      "depth",
+	StopRootDir:    "root dir",
+	StopRootFile:   "root file",
+	StopCallback:   "callback",
+	StopPermission: "permission",
+	StopVanished:   "vanished",
+}
+
+// String returns human readable name of reason.
+func (self StopReason) String() string {
+	if self >= 0 && int(self) < len(stopReasonNames) {
+		return stopReasonNames[self]
+	}
+	return "StopReason(" + strconv.Itoa(int(self)) + ")"
+}
+
+// Stop describes why lookup of .env files stopped.
+type Stop struct {
+	// Reason is a reason why lookup stopped.
+	Reason StopReason
+
+	// Dir is a dir where lookup stopped. Empty string means current dir.
+	Dir string
+
+	// RootFile is a name of file, which matched [Loader.WithRootFiles], if
+	// Reason is [StopRootFile].
+	RootFile string
+}
+
+// Stats contains timing and statistics of [Loader.Load].
+type Stats struct {
+	// WallTime is total duration of Load, including callbacks.
+	WallTime time.Duration
+
+	// Files contains duration of reading and parsing every loaded .env file.
+	Files []FileStats
+
+	// DirsVisited is a number of dirs, checked for .env files.
+	DirsVisited int
+
+	// StatCalls is a number of [Filer.Stat] calls.
+	StatCalls int
+
+	// ReadDirCalls is a number of reads of dirs. Existence of .env files is
+	// checked by entries of their dirs, unless custom [Filer] is configured.
+	ReadDirCalls int
+}
+
+// FileStats contains duration of loading a .env file.
+type FileStats struct {
+	// Name is a name of .env file, like it was passed to godotenv.
+	Name string
+
+	// Duration is a duration of reading and parsing of the file.
+	Duration time.Duration
+}
+
+func (self *Stats) addFile(name string, d time.Duration) {
+	self.Files = append(self.Files, FileStats{Name: name, Duration: d})
+}
@@ -0,0 +1,38 @@
+package dotenv
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+)
+
+// isRootPattern returns true if root file fname is a glob pattern, like
+// "*.workspace".
+func isRootPattern(fname string) bool {
+	return strings.ContainsAny(fname, `*?[\`)
+}
+
+// rootFileInDir checks dir contains root file fname or, if it's a glob pattern,
+// any file matching it. It returns name of found file or empty string.
+func (self *Loader) rootFileInDir(dir, fname string) (string, error) {
+	if !isRootPattern(fname) {
+		if exists, err := self.FileExistsInDir(dir, fname); err != nil {
+			return "", err
+		} else if exists {
+			return fname, nil
+		}
+		return "", nil
+	}
+
+	entries, err := self.readDir(dir)
+	if err != nil {
+		return "", fmt.Errorf("can't read dir '%s': %w", dir, err)
+	}
+
+	for _, entry := range entries {
+		if ok, _ := filepath.Match(fname, entry.Name()); ok {
+			return entry.Name(), nil
+		}
+	}
+	return "", nil
+}
@@ -0,0 +1,49 @@
+package dotenv
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoader_WithRootFiles_pattern(t *testing.T) {
+	dir := valueNoError[string](t)(filepath.EvalSymlinks(t.TempDir()))
+	wsDir := filepath.Join(dir, "ws")
+	subDir := filepath.Join(wsDir, "sub")
+	require.NoError(t, os.MkdirAll(subDir, 0o700))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, ".env"),
+		[]byte("TEST_VAR1=outer\n"), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(wsDir, "app.workspace"), nil,
+		0o600))
+	changeDir(t, subDir)
+
+	tests := []struct {
+		name      string
+		rootFiles []string
+		expect    Stop
+	}{
+		{
+			name:      "pattern",
+			rootFiles: []string{"go.mod", "*.workspace"},
+			expect: Stop{
+				Reason: StopRootFile, Dir: wsDir, RootFile: "app.workspace",
+			},
+		},
+		{
+			name:      "no match",
+			rootFiles: []string{"WORKSPACE*"},
+			expect:    Stop{Reason: StopFound, Dir: dir},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			env := New().WithoutSetenv().WithRootFiles(tt.rootFiles...)
+			require.NoError(t, env.Load())
+			assert.Equal(t, tt.expect, env.Result().Stop)
+		})
+	}
+}
@@ -0,0 +1,137 @@
+package dotenv
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+)
+
+// ErrUnknownKeys returned by [Loader.Load] if .env files define keys, which
+// aren't declared by schema, see [Loader.WithDisallowUnknown].
+var ErrUnknownKeys = errors.New("unknown keys")
+
+// schemaKeys contains keys, declared by a struct, like [Decode] understands
+// them.
+type schemaKeys struct {
+	keys     map[string]struct{}
+	prefixes []string
+
+	// visiting contains struct types on current path of addStruct, so
+	// self-referential types don't recurse forever.
+	visiting map[reflect.Type]bool
+}
+
+// newSchemaKeys returns keys, declared by struct schema, which is a struct or
+// a pointer to struct.
+func newSchemaKeys(schema any, opts ...DecodeOption) (*schemaKeys, error) {
+	d := decoder{sep: DefaultSeparator}
+	for _, opt := range opts {
+		opt(&d)
+	}
+
+	rt := reflect.TypeOf(schema)
+	if rt != nil && rt.Kind() == reflect.Pointer {
+		rt = rt.Elem()
+	}
+	if rt == nil || rt.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("schema %T: %w", schema, ErrNotStructPtr)
+	}
+
+	sk := &schemaKeys{
+		keys:     make(map[string]struct{}),
+		visiting: make(map[reflect.Type]bool),
+	}
+	sk.addStruct(rt, "", d.sep)
+	return sk, nil
+}
+
+func (self *schemaKeys) addStruct(rt reflect.Type, prefix, sep string) {
+	if self.visiting[rt] {
+		return
+	}
+	self.visiting[rt] = true
+	defer delete(self.visiting, rt)
+
+	for i := range rt.NumField() {
+		if name, ok := fieldKey(rt.Field(i)); ok {
+			self.addType(rt.Field(i).Type, prefix+name, sep)
+		}
+	}
+}
+
+func (self *schemaKeys) addType(rt reflect.Type, key, sep string) {
+	for rt.Kind() == reflect.Pointer {
+		rt = rt.Elem()
+	}
+
+	switch rt.Kind() { //nolint:exhaustive // everything else is a scalar
+	case reflect.Struct:
+		if isTextValue(rt) {
+			self.keys[key] = struct{}{}
+			return
+		}
+		self.addStruct(rt, key+sep, sep)
+	case reflect.Map:
+		self.keys[key] = struct{}{}
+		self.prefixes = append(self.prefixes, key+sep)
+	default:
+		self.keys[key] = struct{}{}
+	}
+}
+
+// known returns true if key is declared.
+func (self *schemaKeys) known(key string) bool {
+	if _, ok := self.keys[key]; ok {
+		return true
+	}
+	return slices.ContainsFunc(self.prefixes, func(prefix string) bool {
+		return strings.HasPrefix(key, prefix)
+	})
+}
+
+// WithDisallowUnknown configures [Loader.Load] to return an error wrapping
+// [ErrUnknownKeys], if .env files define keys, which aren't declared by
+// schema. schema is a struct or a pointer to struct, like [Decode] expects,
+// and opts are the same as for [Decode]. For instance:
+//
+//	cfg := struct {
+//		DatabaseURL string `env:"DATABASE_URL"`
+//	}{}
+//
+//	err := dotenv.New().WithDisallowUnknown(&cfg).Load()
+//
+// returns an error, if any .env file has a typo like "DATABSE_URL", which
+// otherwise silently leaves the real key at its default. Such .env file is
+// not applied at all.
+//
+// If schema isn't a struct, Load returns an error.
+func (self *Loader) WithDisallowUnknown(schema any, opts ...DecodeOption,
+) *Loader {
+	self.schema, self.schemaErr = newSchemaKeys(schema, opts...)
+	return self
+}
+
+// checkUnknownKeys returns an error if vars from fname contain any key, which
+// isn't declared by configured schema.
+func (self *Loader) checkUnknownKeys(fname string, vars map[string]string,
+) error {
+	if self.schema == nil {
+		return nil
+	}
+
+	var unknown []string
+	for key := range vars {
+		if !self.schema.known(key) {
+			unknown = append(unknown, key)
+		}
+	}
+	if len(unknown) == 0 {
+		return nil
+	}
+
+	slices.Sort(unknown)
+	return fmt.Errorf("%w in %v: %v", ErrUnknownKeys, fname,
+		strings.Join(unknown, ", "))
+}
@@ -0,0 +1,93 @@
+package dotenv
+
+import (
+	"net/url"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewSchemaKeys(t *testing.T) {
+	type db struct {
+		Host string
+	}
+
+	schema := struct {
+		Name    string `env:"APP_NAME"`
+		Skipped string `env:"-"`
+		DB      db
+		Replica *db
+		Labels  map[string]string
+		Hosts   []string
+		private string
+	}{}
+
+	sk, err := newSchemaKeys(&schema)
+	require.NoError(t, err)
+	for _, key := range []string{
+		"APP_NAME", "DB__HOST", "REPLICA__HOST", "LABELS", "LABELS__A", "HOSTS",
+	} {
+		assert.True(t, sk.known(key), key)
+	}
+	for _, key := range []string{"NAME", "SKIPPED", "DB", "PRIVATE", "LABELS_A"} {
+		assert.False(t, sk.known(key), key)
+	}
+
+	sk, err = newSchemaKeys(schema, WithSeparator("."))
+	require.NoError(t, err)
+	assert.True(t, sk.known("DB.HOST"))
+	assert.False(t, sk.known("DB__HOST"))
+
+	_, err = newSchemaKeys("not a struct")
+	require.ErrorIs(t, err, ErrNotStructPtr)
+
+	_, err = newSchemaKeys(nil)
+	require.ErrorIs(t, err, ErrNotStructPtr)
+}
+
+func TestNewSchemaKeys_recursive(t *testing.T) {
+	type node struct {
+		Name string
+		Next *node
+	}
+
+	sk, err := newSchemaKeys(&node{})
+	require.NoError(t, err)
+	assert.True(t, sk.known("NAME"))
+	assert.False(t, sk.known("NEXT__NAME"))
+	assert.Empty(t, sk.visiting)
+}
+
+func TestLoader_WithDisallowUnknown(t *testing.T) {
+	changeDir(t, "testdata")
+	restoreEnvVars(t)
+
+	var cfg struct {
+		Var1 string `env:"TEST_VAR1"`
+	}
+	env := New()
+	assert.Same(t, env, env.WithDisallowUnknown(&cfg))
+	err := env.Load()
+	require.ErrorIs(t, err, ErrUnknownKeys)
+	assert.Contains(t, err.Error(), "TEST_VAR2")
+	_, ok := os.LookupEnv(allEnvVars[0])
+	assert.False(t, ok)
+
+	var cfg2 struct {
+		Var1 string `env:"TEST_VAR1"`
+		Var2 string `env:"TEST_VAR2"`
+	}
+	require.NoError(t, env.WithDisallowUnknown(&cfg2).Load())
+	assert.Equal(t, "testdata", os.Getenv(allEnvVars[0]))
+
+	require.ErrorIs(t, env.WithDisallowUnknown(1).Load(), ErrInvalidConfig)
+}
+
+func TestLoader_WithDisallowUnknown_textValues(t *testing.T) {
+	sk, err := newSchemaKeys(&struct{ Endpoint url.URL }{})
+	require.NoError(t, err)
+	assert.True(t, sk.known("ENDPOINT"))
+	assert.False(t, sk.known("ENDPOINT__HOST"))
+}
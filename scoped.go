@@ -0,0 +1,67 @@
+package dotenv
+
+import (
+	"fmt"
+	"path/filepath"
+)
+
+// ScopedEnv is an independent view of environment of a project dir, returned by
+// [Loader.For].
+type ScopedEnv struct {
+	Env
+
+	// Dir is absolute path of project dir, where lookup started.
+	Dir string
+
+	result *Result
+}
+
+// Result returns result of loading of .env files for Dir.
+func (self *ScopedEnv) Result() *Result { return self.result }
+
+// For loads .env files for project dir like [Loader.Load] does, as if dir is
+// current dir, but without changing of current dir and without calling
+// [os.Setenv]. It returns merged view of the process environment and loaded env
+// vars, like [Loader.RunIsolated] does. Every call uses a copy of this
+// [Loader] configuration, so one process can keep views of many projects, like
+// monorepo tools and language servers do:
+//
+//	env := dotenv.New().WithEnvSuffix("dev")
+//	for _, dir := range projects {
+//		scoped, err := env.For(dir)
+//		if err != nil {
+//			return err
+//		}
+//		fmt.Println(dir, scoped.Getenv("DATABASE_URL"))
+//	}
+//
+// Relative dir is relative to current dir or to root of [Loader.WithFS].
+func (self *Loader) For(dir string) (*ScopedEnv, error) {
+	absDir, err := self.absDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("scoped env for %v: %w", dir, err)
+	}
+
+	scoped := *self
+	scoped.startAt, scoped.noSetenv = absDir, true
+	scoped.vars, scoped.lastDir = nil, ""
+	scoped.result, scoped.recording = nil, nil
+	if err := scoped.Load(); err != nil {
+		return nil, err
+	}
+
+	return &ScopedEnv{
+		Env:    Env{vars: scoped.environ()},
+		Dir:    absDir,
+		result: scoped.result,
+	}, nil
+}
+
+// absDir returns absolute path of dir, which is relative to current dir or to
+// root of configured [fs.FS].
+func (self *Loader) absDir(dir string) (string, error) {
+	if self.fsys != nil {
+		return filepath.Join(string(filepath.Separator), fsName(dir)), nil
+	}
+	return filepath.Abs(dir) //nolint:wrapcheck // caller wraps it
+}
@@ -0,0 +1,53 @@
+package dotenv
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoader_For(t *testing.T) {
+	restoreEnvVars(t)
+	wd, err := os.Getwd()
+	require.NoError(t, err)
+
+	env := New().WithEnvSuffix("test")
+	g, err := env.For("testdata/g")
+	require.NoError(t, err)
+	j, err := env.For(filepath.Join(wd, "testdata/j/k"))
+	require.NoError(t, err)
+
+	assert.Equal(t, filepath.Join(wd, "testdata/g"), g.Dir)
+	assert.Equal(t, "local", g.Getenv(allEnvVars[0]))
+	assert.Equal(t, "test", g.Getenv(allEnvVars[1]))
+	assert.Equal(t, "env", j.Getenv(allEnvVars[0]))
+	assert.Equal(t, Stop{Reason: StopFound, Dir: filepath.Join(wd, "testdata/j")},
+		j.Result().Stop)
+
+	for _, name := range allEnvVars {
+		assert.Empty(t, os.Getenv(name), name)
+		assert.Empty(t, env.Getenv(name), name)
+	}
+	assert.Nil(t, env.Result())
+	cwd, err := os.Getwd()
+	require.NoError(t, err)
+	assert.Equal(t, wd, cwd)
+}
+
+func TestLoader_For_processEnv(t *testing.T) {
+	restoreEnvVars(t)
+	t.Setenv(allEnvVars[0], "env")
+
+	scoped, err := New().For("testdata/g")
+	require.NoError(t, err)
+	assert.Equal(t, "env", scoped.Getenv(allEnvVars[0]))
+	assert.Equal(t, "second", scoped.Getenv(allEnvVars[1]))
+}
+
+func TestLoader_For_error(t *testing.T) {
+	_, err := New().WithEnvSuffix("a/b").For("testdata/g")
+	require.ErrorIs(t, err, ErrInvalidConfig)
+}
@@ -0,0 +1,60 @@
+package dotenv
+
+import (
+	"log/slog"
+	"path"
+	"slices"
+)
+
+// redacted replaces values of [Secret] in any output.
+const redacted = "***"
+
+// Secret is a string, which never leaks into logs. It implements
+// [fmt.Stringer], [fmt.GoStringer], [slog.LogValuer] and [json.Marshaler] and
+// all of them return "***". Use [Secret.Value] to get the real value.
+//
+// [Decode] decodes fields of type Secret like strings. See also
+// [WithSecretKeys].
+type Secret string
+
+// Value returns the real value of secret.
+func (self Secret) Value() string { return string(self) }
+
+// String implements [fmt.Stringer].
+func (self Secret) String() string { return redacted }
+
+// GoString implements [fmt.GoStringer].
+func (self Secret) GoString() string { return redacted }
+
+// LogValue implements [slog.LogValuer].
+func (self Secret) LogValue() slog.Value { return slog.StringValue(redacted) }
+
+// MarshalJSON implements [json.Marshaler].
+func (self Secret) MarshalJSON() ([]byte, error) {
+	return []byte(`"` + redacted + `"`), nil
+}
+
+// WithSecretKeys configures [Decode] to decode values of keys, matching any
+// of patterns, as [Secret], when it decodes into fields of type any or
+// map[string]any. Patterns use syntax of [path.Match], like "*_PASSWORD" or
+// "*TOKEN*".
+func WithSecretKeys(patterns ...string) DecodeOption {
+	return func(d *decoder) { d.secrets = patterns }
+}
+
+// isSecret returns true if key matches any of configured secret patterns.
+func (self *decoder) isSecret(key string) bool {
+	return slices.ContainsFunc(self.secrets, func(pattern string) bool {
+		ok, _ := path.Match(pattern, key)
+		return ok
+	})
+}
+
+// anyValue returns value of key for fields of type any: [Secret] if key is a
+// secret, or string.
+func (self *decoder) anyValue(key, value string) any {
+	if self.isSecret(key) {
+		return Secret(value)
+	}
+	return value
+}
@@ -0,0 +1,95 @@
+package dotenv
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"strings"
+)
+
+// secretsExt is an extension of .env files with secrets.
+const secretsExt = ".secrets"
+
+// ErrUnsafeSecrets returned by [Loader.Load] if a .env file with secrets isn't
+// ignored by git or can be read by group or others.
+var ErrUnsafeSecrets = errors.New("unsafe secrets file")
+
+// WithSecretsEnabled configures [Loader.Load] to load a parallel cascade of .env
+// files with secrets, like:
+//
+//	.env.production.secrets
+//	.env.secrets
+//
+// They are loaded before regular .env files, so secrets win. Every file with
+// secrets must be ignored by git, if it's inside of git work tree, and must
+// not be readable by group or others, otherwise Load returns an error wrapping
+// [ErrUnsafeSecrets]. It institutionalizes common split of values and secrets.
+func (self *Loader) WithSecretsEnabled() *Loader {
+	self.secretsEnabled = true
+	return self
+}
+
+// secretFiles returns names of .env files with secrets.
+func (self *Loader) secretFiles() []string {
+	base := self.baseName()
+	if self.envSuffix == "" {
+		return []string{base + secretsExt}
+	}
+	return []string{
+		base + "." + self.envSuffix + secretsExt,
+		base + secretsExt,
+	}
+}
+
+// checkSecretsFile returns an error if fname is a .env file with secrets and
+// it isn't safe.
+func (self *Loader) checkSecretsFile(fname string) error {
+	if !self.secretsEnabled || !strings.HasSuffix(fname, secretsExt) ||
+		self.fsys != nil {
+		return nil
+	}
+
+	if runtime.GOOS != "windows" {
+		if fi, err := os.Stat(fname); err != nil {
+			return fmt.Errorf("check %v: %w", fname, err)
+		} else if fi.Mode().Perm()&0o077 != 0 {
+			return fmt.Errorf("%w: %v has permissions %v, expected 0600 or less",
+				ErrUnsafeSecrets, fname, fi.Mode().Perm())
+		}
+	}
+
+	if ignored, ok := gitIgnored(fname); ok && !ignored {
+		return fmt.Errorf("%w: %v isn't ignored by git", ErrUnsafeSecrets, fname)
+	}
+	return nil
+}
+
+// gitIgnored returns true if fname is ignored by git. ok is false, if it can't
+// be checked, because git isn't installed or fname isn't in git work tree.
+func gitIgnored(fname string) (ignored, ok bool) {
+	cmd := exec.Command("git", "check-ignore", "-q", filepath.Base(fname))
+	cmd.Dir = filepath.Dir(fname)
+	err := cmd.Run()
+	if err == nil {
+		return true, true
+	}
+
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) && exitErr.ExitCode() == 1 {
+		return false, true
+	}
+	return false, false
+}
+
+// withSecretFiles returns envs with .env files with secrets before them, if
+// they are enabled.
+func (self *Loader) withSecretFiles(envs []string) []string {
+	if !self.secretsEnabled {
+		return envs
+	}
+	return slices.Concat(self.secretFiles(), envs)
+}
@@ -0,0 +1,64 @@
+package dotenv
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoader_WithSecretsEnabled_envFiles(t *testing.T) {
+	env := New()
+	assert.Equal(t, []string{".env.local", ".env"}, env.envFiles())
+
+	assert.Same(t, env, env.WithSecretsEnabled())
+	assert.Equal(t, []string{".env.secrets", ".env.local", ".env"},
+		env.envFiles())
+
+	env.WithEnvSuffix("production")
+	assert.Equal(t, []string{
+		".env.production.secrets", ".env.secrets",
+		".env.production.local", ".env.local", ".env.production", ".env",
+	}, env.envFiles())
+}
+
+func TestLoader_WithSecretsEnabled(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("permissions aren't checked on windows")
+	}
+
+	dir := t.TempDir()
+	fname := filepath.Join(dir, ".env.secrets")
+	require.NoError(t, os.WriteFile(fname, []byte("FOO=secret\n"), 0o600))
+
+	env := New().WithSecretsEnabled()
+	vars, err := env.readFile(fname)
+	require.NoError(t, err, "not in git work tree")
+	assert.Equal(t, map[string]string{"FOO": "secret"}, vars)
+
+	require.NoError(t, os.Chmod(fname, 0o640))
+	_, err = env.readFile(fname)
+	require.ErrorIs(t, err, ErrUnsafeSecrets)
+	assert.ErrorContains(t, err, "permissions")
+	require.NoError(t, os.Chmod(fname, 0o600))
+
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not found")
+	}
+	cmd := exec.Command("git", "init", "-q")
+	cmd.Dir = dir
+	require.NoError(t, cmd.Run())
+
+	_, err = env.readFile(fname)
+	require.ErrorIs(t, err, ErrUnsafeSecrets)
+	assert.ErrorContains(t, err, "isn't ignored by git")
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, ".gitignore"),
+		[]byte("*.secrets\n"), 0o600))
+	_, err = env.readFile(fname)
+	require.NoError(t, err)
+}
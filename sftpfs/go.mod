@@ -0,0 +1,20 @@
+module github.com/dsh2dsh/expx-dotenv/sftpfs
+
+go 1.23
+
+require (
+	github.com/dsh2dsh/expx-dotenv v0.0.0-20261015091428-563543f1cf1b
+	github.com/pkg/sftp v1.13.7
+	github.com/stretchr/testify v1.10.0
+)
+
+require (
+	github.com/davecgh/go-spew v1.1.1 // indirect
+	github.com/fsnotify/fsnotify v1.10.1 // indirect
+	github.com/joho/godotenv v1.5.1 // indirect
+	github.com/kr/fs v0.1.0 // indirect
+	github.com/pmezard/go-difflib v1.0.0 // indirect
+	golang.org/x/crypto v0.31.0 // indirect
+	golang.org/x/sys v0.28.0 // indirect
+	gopkg.in/yaml.v3 v3.0.1 // indirect
+)
@@ -0,0 +1,112 @@
+// Package sftpfs is an experimental adapter of SFTP client to [fs.FS], so tools
+// operating on remote checkouts, like deployment runners and remote dev CLIs,
+// can run the same lookup and cascade of .env files against a remote dir tree
+// using [github.com/dsh2dsh/expx-dotenv.Loader.WithFS]:
+//
+//	conn, err := ssh.Dial("tcp", "host:22", sshConfig)
+//	if err != nil {
+//		return err
+//	}
+//	defer conn.Close()
+//
+//	client, err := sftp.NewClient(conn)
+//	if err != nil {
+//		return err
+//	}
+//	defer client.Close()
+//
+//	env := dotenv.New().WithFS(sftpfs.New(client, "/srv/checkout"))
+//	scoped, err := env.For("services/api")
+//
+// It is a separate module, so only its users depend on SFTP client. API of
+// this package can change.
+package sftpfs
+
+import (
+	"errors"
+	"io/fs"
+	"path"
+	"slices"
+	"strings"
+
+	"github.com/pkg/sftp"
+)
+
+// FS implements [fs.FS], [fs.StatFS] and [fs.ReadDirFS] using SFTP client. Its
+// root is a remote dir.
+type FS struct {
+	client *sftp.Client
+	root   string
+}
+
+// New creates and returns [FS] with remote dir root as its root. Relative root
+// is relative to working dir of SFTP session.
+func New(client *sftp.Client, root string) *FS {
+	return &FS{client: client, root: root}
+}
+
+// Open opens remote file name for reading.
+func (self *FS) Open(name string) (fs.File, error) {
+	fpath, err := self.remotePath("open", name)
+	if err != nil {
+		return nil, err
+	}
+
+	f, err := self.client.Open(fpath)
+	if err != nil {
+		return nil, pathError("open", name, err)
+	}
+	return f, nil
+}
+
+// Stat returns [fs.FileInfo] of remote file name, following symlinks.
+func (self *FS) Stat(name string) (fs.FileInfo, error) {
+	fpath, err := self.remotePath("stat", name)
+	if err != nil {
+		return nil, err
+	}
+
+	fi, err := self.client.Stat(fpath)
+	if err != nil {
+		return nil, pathError("stat", name, err)
+	}
+	return fi, nil
+}
+
+// ReadDir reads remote dir name and returns its entries sorted by name.
+func (self *FS) ReadDir(name string) ([]fs.DirEntry, error) {
+	fpath, err := self.remotePath("readdir", name)
+	if err != nil {
+		return nil, err
+	}
+
+	infos, err := self.client.ReadDir(fpath)
+	if err != nil {
+		return nil, pathError("readdir", name, err)
+	}
+
+	entries := make([]fs.DirEntry, len(infos))
+	for i, fi := range infos {
+		entries[i] = fs.FileInfoToDirEntry(fi)
+	}
+	slices.SortFunc(entries, func(a, b fs.DirEntry) int {
+		return strings.Compare(a.Name(), b.Name())
+	})
+	return entries, nil
+}
+
+// remotePath returns remote path of name of [fs.FS].
+func (self *FS) remotePath(op, name string) (string, error) {
+	if !fs.ValidPath(name) {
+		return "", &fs.PathError{Op: op, Path: name, Err: fs.ErrInvalid}
+	}
+	return path.Join(self.root, name), nil
+}
+
+// pathError returns err of op with name as [fs.PathError].
+func pathError(op, name string, err error) error {
+	if pathErr := (*fs.PathError)(nil); errors.As(err, &pathErr) {
+		err = pathErr.Err
+	}
+	return &fs.PathError{Op: op, Path: name, Err: err}
+}
@@ -0,0 +1,76 @@
+package sftpfs
+
+import (
+	"io/fs"
+	"net"
+	"os"
+	"path"
+	"testing"
+
+	"github.com/pkg/sftp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	dotenv "github.com/dsh2dsh/expx-dotenv"
+)
+
+func TestFS(t *testing.T) {
+	client := newClient(t)
+	writeFile(t, client, "/srv/checkout/go.mod", "module example\n")
+	writeFile(t, client, "/srv/checkout/.env", "TEST_VAR1=root\nTEST_VAR2=root\n")
+	writeFile(t, client, "/srv/checkout/api/.env.local", "TEST_VAR1=local\n")
+	require.NoError(t, client.MkdirAll("/srv/checkout/api/cmd"))
+
+	fsys := New(client, "/srv/checkout")
+	b, err := fs.ReadFile(fsys, "api/.env.local")
+	require.NoError(t, err)
+	assert.Equal(t, "TEST_VAR1=local\n", string(b))
+
+	fi, err := fs.Stat(fsys, "api")
+	require.NoError(t, err)
+	assert.True(t, fi.IsDir())
+
+	_, err = fs.Stat(fsys, "api/.env")
+	require.ErrorIs(t, err, fs.ErrNotExist)
+	_, err = fsys.Open("../etc/passwd")
+	require.ErrorIs(t, err, fs.ErrInvalid)
+
+	entries, err := fs.ReadDir(fsys, "api")
+	require.NoError(t, err)
+	var names []string
+	for _, e := range entries {
+		names = append(names, e.Name())
+	}
+	assert.Equal(t, []string{".env.local", "cmd"}, names)
+
+	scoped, err := dotenv.New().WithFS(fsys).For("api/cmd")
+	require.NoError(t, err)
+	assert.Equal(t, "local", scoped.Getenv("TEST_VAR1"))
+	assert.Equal(t, "/api", scoped.Result().Stop.Dir)
+
+	require.NoError(t, client.MkdirAll("/srv/checkout/web"))
+	scoped, err = dotenv.New().WithFS(fsys).For("web")
+	require.NoError(t, err)
+	assert.Equal(t, "root", scoped.Getenv("TEST_VAR2"))
+}
+
+func newClient(t *testing.T) *sftp.Client {
+	serverConn, clientConn := net.Pipe()
+	server := sftp.NewRequestServer(serverConn, sftp.InMemHandler())
+	go server.Serve() //nolint:errcheck // it fails, when client closed
+	t.Cleanup(func() { server.Close() })
+
+	client, err := sftp.NewClientPipe(clientConn, clientConn)
+	require.NoError(t, err)
+	t.Cleanup(func() { client.Close() })
+	return client
+}
+
+func writeFile(t *testing.T, client *sftp.Client, fname, content string) {
+	require.NoError(t, client.MkdirAll(path.Dir(fname)))
+	f, err := client.OpenFile(fname, os.O_WRONLY|os.O_CREATE|os.O_TRUNC)
+	require.NoError(t, err)
+	_, err = f.Write([]byte(content))
+	require.NoError(t, err)
+	require.NoError(t, f.Close())
+}
@@ -0,0 +1,78 @@
+package dotenv
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+
+	"github.com/joho/godotenv"
+)
+
+// ErrNotLoaded returned by [Loader.WriteSnapshot] if [Loader.Load] wasn't
+// called yet.
+var ErrNotLoaded = errors.New("nothing loaded yet")
+
+// WriteSnapshot writes all env vars, defined by .env files of last
+// [Loader.Load], into file path, readable by owner only. It contains
+// effective values, so if an env var was defined before Load, snapshot
+// contains value from the environment, not from .env file. Later Load can
+// consume it using [Loader.WithSnapshotFallback]. Values of secret keys can be
+// encrypted, see [Loader.WithExportEncryption].
+func (self *Loader) WriteSnapshot(path string) error {
+	if self.loaded == nil {
+		return ErrNotLoaded
+	}
+
+	vars, err := self.exportVars()
+	if err != nil {
+		return fmt.Errorf("write snapshot: %w", err)
+	}
+
+	s, err := godotenv.Marshal(vars)
+	if err != nil {
+		return fmt.Errorf("marshal snapshot: %w", err)
+	} else if err := os.WriteFile(path, []byte(s+"\n"), 0o600); err != nil {
+		return fmt.Errorf("write snapshot: %w", err)
+	}
+	return nil
+}
+
+// WithSnapshotFallback configures [Loader.Load] to load snapshot file path,
+// written by [Loader.WriteSnapshot] before, if it found no .env files or
+// failed loading them. In last case the error is kept in [Result.Warnings].
+//
+// Errors of checks, which defend against tampering or misconfiguration, aren't
+// replaced by the snapshot: Load returns errors wrapping [ErrChecksum],
+// [ErrForeignOwner], [ErrUnsafeSecrets], [ErrMissingFiles] and [ErrNoEnvFiles]
+// as is.
+func (self *Loader) WithSnapshotFallback(path string) *Loader {
+	self.snapshotFallback = path
+	return self
+}
+
+// noFallbackErrs are errors of loading .env files, which snapshot fallback
+// must not hide.
+var noFallbackErrs = [...]error{
+	ErrChecksum, ErrForeignOwner, ErrUnsafeSecrets, ErrMissingFiles,
+	ErrNoEnvFiles,
+}
+
+// loadSnapshot loads configured snapshot file instead of .env files, which
+// failed with loadErr or weren't found, if loadErr is nil.
+func (self *Loader) loadSnapshot(loadErr error) error {
+	if slices.ContainsFunc(noFallbackErrs[:], func(target error) bool {
+		return errors.Is(loadErr, target)
+	}) {
+		return loadErr
+	}
+
+	ref := SourceRef{Kind: SourceSnapshot, Name: self.snapshotFallback}
+	if err := self.applySource(ref); err != nil {
+		return errors.Join(loadErr,
+			fmt.Errorf("load snapshot fallback: %w", err))
+	} else if loadErr != nil {
+		self.warnings = append(self.warnings, loadErr)
+	}
+	return nil
+}
@@ -0,0 +1,124 @@
+package dotenv
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoader_WriteSnapshot(t *testing.T) {
+	snapshot := filepath.Join(t.TempDir(), "snapshot.env")
+	env := New()
+	require.ErrorIs(t, env.WriteSnapshot(snapshot), ErrNotLoaded)
+
+	changeDir(t, "testdata")
+	restoreEnvVars(t)
+	t.Setenv(allEnvVars[1], "from env")
+	require.NoError(t, env.Load())
+	require.NoError(t, env.WriteSnapshot(snapshot))
+
+	b := valueNoError[[]byte](t)(os.ReadFile(snapshot))
+	assert.Equal(t, "TEST_VAR1=\"testdata\"\nTEST_VAR2=\"from env\"\n", string(b))
+	info := valueNoError[os.FileInfo](t)(os.Stat(snapshot))
+	assert.Equal(t, os.FileMode(0o600), info.Mode().Perm())
+
+	require.Error(t, env.WriteSnapshot(filepath.Join(snapshot, "a")))
+}
+
+func TestLoader_WithSnapshotFallback(t *testing.T) {
+	snapshot := filepath.Join(t.TempDir(), "snapshot.env")
+	require.NoError(t, os.WriteFile(snapshot,
+		[]byte("TEST_VAR1=snapshot\n"), 0o600))
+
+	tests := []struct {
+		name     string
+		dir      string
+		before   func(env *Loader)
+		expect   string
+		warnings int
+	}{
+		{
+			name:   "found .env files",
+			dir:    "testdata/a",
+			expect: "testdata",
+		},
+		{
+			name:   "nothing found",
+			dir:    "testdata/a",
+			before: func(env *Loader) { env.WithDepth(1) },
+			expect: "snapshot",
+		},
+		{
+			name:     "load error",
+			dir:      "testdata/d",
+			expect:   "snapshot",
+			warnings: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			changeDir(t, tt.dir)
+			restoreEnvVars(t)
+			env := New()
+			assert.Same(t, env, env.WithSnapshotFallback(snapshot))
+			if tt.before != nil {
+				tt.before(env)
+			}
+			require.NoError(t, env.Load())
+			assert.Equal(t, tt.expect, os.Getenv(allEnvVars[0]))
+			assert.Len(t, env.Result().Warnings, tt.warnings)
+		})
+	}
+}
+
+func TestLoader_WithSnapshotFallback_error(t *testing.T) {
+	changeDir(t, "testdata/d")
+	restoreEnvVars(t)
+	env := New().WithSnapshotFallback(filepath.Join(t.TempDir(), "not-exists"))
+	require.ErrorIs(t, env.Load(), os.ErrNotExist)
+}
+
+func TestLoader_WithSnapshotFallback_noFallback(t *testing.T) {
+	snapshot := filepath.Join(t.TempDir(), "snapshot.env")
+	require.NoError(t, os.WriteFile(snapshot,
+		[]byte("TEST_VAR1=snapshot\n"), 0o600))
+
+	tests := []struct {
+		name   string
+		before func(env *Loader)
+		err    error
+	}{
+		{
+			name: "checksum mismatch",
+			before: func(env *Loader) {
+				env.WithPinnedChecksums(map[string]string{".env": "00"})
+			},
+			err: ErrChecksum,
+		},
+		{
+			name:   "missing files",
+			before: func(env *Loader) { env.WithRequiredFiles(".env.local") },
+			err:    ErrMissingFiles,
+		},
+		{
+			name:   "no .env files",
+			before: func(env *Loader) { env.WithDepth(1).WithRequired() },
+			err:    ErrNoEnvFiles,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			changeDir(t, "testdata/a")
+			restoreEnvVars(t)
+			env := New().WithSnapshotFallback(snapshot)
+			tt.before(env)
+			require.ErrorIs(t, env.Load(), tt.err)
+			assert.NotEqual(t, "snapshot", os.Getenv(allEnvVars[0]))
+		})
+	}
+}
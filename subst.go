@@ -0,0 +1,110 @@
+package dotenv
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// ErrUnsetVar returned by [Loader.Substitute] for ${VAR:?message}, if VAR is
+// unset or empty.
+var ErrUnsetVar = errors.New("unset variable")
+
+// Substitute copies r to w, like GNU envsubst does, replacing references to
+// env vars from merged environment, see [Loader.LookupEnv]:
+//
+//   - $VAR and ${VAR} are replaced by value of VAR or empty string.
+//   - ${VAR:-default} is replaced by "default" if VAR is unset or empty.
+//   - ${VAR:?message} returns an error with "message" if VAR is unset or empty.
+//
+// Default values and messages are used as is, without substitution. "$" not
+// followed by a name or "{" is copied as is.
+func (self *Loader) Substitute(r io.Reader, w io.Writer) error {
+	b, err := io.ReadAll(r)
+	if err != nil {
+		return fmt.Errorf("substitute: %w", err)
+	}
+
+	var out bytes.Buffer
+	out.Grow(len(b))
+	for len(b) > 0 {
+		i := bytes.IndexByte(b, '$')
+		if i < 0 {
+			out.Write(b)
+			break
+		}
+		out.Write(b[:i])
+		b = b[i+1:]
+
+		var value string
+		if b, value, err = self.substRef(b); err != nil {
+			return err
+		}
+		out.WriteString(value)
+	}
+
+	if _, err := out.WriteTo(w); err != nil {
+		return fmt.Errorf("substitute: %w", err)
+	}
+	return nil
+}
+
+// substRef expands reference to env var at the beginning of b, which follows
+// "$", and returns the rest of b and expanded value.
+func (self *Loader) substRef(b []byte) ([]byte, string, error) {
+	if len(b) > 0 && b[0] == '{' {
+		end := bytes.IndexByte(b, '}')
+		if end < 0 {
+			return nil, "", errors.New("substitute: unclosed ${")
+		}
+		value, err := self.substExpr(string(b[1:end]))
+		return b[end+1:], value, err
+	}
+
+	n := varNameLen(b)
+	if n == 0 {
+		return b, "$", nil
+	}
+	return b[n:], self.Getenv(string(b[:n])), nil
+}
+
+// substExpr expands expression inside of ${...}.
+func (self *Loader) substExpr(expr string) (string, error) {
+	n := varNameLen([]byte(expr))
+	if n == 0 {
+		return "", fmt.Errorf("substitute: bad reference ${%v}", expr)
+	}
+
+	name, op := expr[:n], expr[n:]
+	value := self.Getenv(name)
+	switch {
+	case op == "":
+		return value, nil
+	case strings.HasPrefix(op, ":-"):
+		if value == "" {
+			return op[2:], nil
+		}
+		return value, nil
+	case strings.HasPrefix(op, ":?"):
+		if value == "" {
+			return "", fmt.Errorf("substitute: %v: %w: %v", name, ErrUnsetVar, op[2:])
+		}
+		return value, nil
+	}
+	return "", fmt.Errorf("substitute: bad reference ${%v}", expr)
+}
+
+// varNameLen returns length of env var name at the beginning of b or 0.
+func varNameLen(b []byte) int {
+	for i, c := range b {
+		switch {
+		case c == '_', 'A' <= c && c <= 'Z', 'a' <= c && c <= 'z':
+		case '0' <= c && c <= '9' && i > 0:
+		default:
+			return i
+		}
+	}
+	return len(b)
+}
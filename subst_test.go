@@ -0,0 +1,92 @@
+package dotenv
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoader_Substitute(t *testing.T) {
+	t.Setenv("TEST_HOST", "example.com")
+	t.Setenv("TEST_EMPTY", "")
+	env := New().WithoutSetenv()
+	env.vars = map[string]string{"TEST_PORT": "8080"}
+
+	tests := []struct {
+		name    string
+		input   string
+		expect  string
+		wantErr bool
+		errIs   error
+	}{
+		{
+			name:   "plain",
+			input:  "no refs",
+			expect: "no refs",
+		},
+		{
+			name:   "simple",
+			input:  "http://$TEST_HOST:${TEST_PORT}/",
+			expect: "http://example.com:8080/",
+		},
+		{
+			name:   "unset",
+			input:  "[$TEST_NOT_EXISTS][${TEST_NOT_EXISTS}]",
+			expect: "[][]",
+		},
+		{
+			name:   "default",
+			input:  "${TEST_NOT_EXISTS:-a b} ${TEST_EMPTY:-c} ${TEST_HOST:-d}",
+			expect: "a b c example.com",
+		},
+		{
+			name:   "required",
+			input:  "${TEST_HOST:?host required}",
+			expect: "example.com",
+		},
+		{
+			name:    "required unset",
+			input:   "${TEST_EMPTY:?empty}",
+			wantErr: true,
+			errIs:   ErrUnsetVar,
+		},
+		{
+			name:   "lone dollar",
+			input:  "cost $5 $ $",
+			expect: "cost $5 $ $",
+		},
+		{
+			name:    "unclosed",
+			input:   "${TEST_HOST",
+			wantErr: true,
+		},
+		{
+			name:    "bad name",
+			input:   "${1A}",
+			wantErr: true,
+		},
+		{
+			name:    "bad operator",
+			input:   "${TEST_HOST:+x}",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out bytes.Buffer
+			err := env.Substitute(strings.NewReader(tt.input), &out)
+			if !tt.wantErr {
+				require.NoError(t, err)
+				assert.Equal(t, tt.expect, out.String())
+			} else if tt.errIs != nil {
+				require.ErrorIs(t, err, tt.errIs)
+			} else {
+				require.Error(t, err)
+			}
+		})
+	}
+}
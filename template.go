@@ -0,0 +1,54 @@
+package dotenv
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"text/template"
+)
+
+// RenderTemplate renders [text/template] file src into file dst, with merged
+// environment as data, see [Loader.LookupEnv]. So the same env cascade can
+// drive generation of nginx configs, app.yaml and so on. For instance:
+//
+//	server {
+//		listen {{ .PORT }};
+//		server_name {{ default "localhost" (env "SERVER_NAME") }};
+//	}
+//
+// Missing keys like {{ .PORT }} are errors. Template can use functions:
+//
+//   - env "KEY" returns value of KEY or empty string, if it doesn't exist.
+//   - default "value" s returns s or "value", if s is empty.
+//
+// dst is written only if rendering succeeded and it gets permissions of src.
+func (self *Loader) RenderTemplate(src, dst string) error {
+	info, err := os.Stat(src)
+	if err != nil {
+		return fmt.Errorf("render template: %w", err)
+	}
+
+	tmpl, err := template.New(filepath.Base(src)).Funcs(template.FuncMap{
+		"env":     self.Getenv,
+		"default": templateDefault,
+	}).Option("missingkey=error").ParseFiles(src)
+	if err != nil {
+		return fmt.Errorf("parse template %v: %w", src, err)
+	}
+
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, self.environ()); err != nil {
+		return fmt.Errorf("render template %v: %w", src, err)
+	} else if err := os.WriteFile(dst, buf.Bytes(), info.Mode().Perm()); err != nil {
+		return fmt.Errorf("render template %v: %w", src, err)
+	}
+	return nil
+}
+
+func templateDefault(def, s string) string {
+	if s == "" {
+		return def
+	}
+	return s
+}
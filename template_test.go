@@ -0,0 +1,56 @@
+package dotenv
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoader_RenderTemplate(t *testing.T) {
+	t.Setenv("TEST_PORT", "8080")
+	env := New().WithoutSetenv()
+	env.vars = map[string]string{"TEST_NAME": "app"}
+
+	dir := t.TempDir()
+	src := filepath.Join(dir, "nginx.conf.tmpl")
+	dst := filepath.Join(dir, "nginx.conf")
+	require.NoError(t, os.WriteFile(src, []byte(
+		`listen {{ .TEST_PORT }}; name {{ .TEST_NAME }}; `+
+			`host {{ default "localhost" (env "TEST_HOST") }};`), 0o640))
+
+	require.NoError(t, env.RenderTemplate(src, dst))
+	b := valueNoError[[]byte](t)(os.ReadFile(dst))
+	assert.Equal(t, "listen 8080; name app; host localhost;", string(b))
+	info := valueNoError[os.FileInfo](t)(os.Stat(dst))
+	assert.Equal(t, os.FileMode(0o640), info.Mode().Perm())
+
+	t.Setenv("TEST_HOST", "example.com")
+	require.NoError(t, env.RenderTemplate(src, dst))
+	b = valueNoError[[]byte](t)(os.ReadFile(dst))
+	assert.Equal(t, "listen 8080; name app; host example.com;", string(b))
+}
+
+func TestLoader_RenderTemplate_errors(t *testing.T) {
+	dir := t.TempDir()
+	dst := filepath.Join(dir, "out")
+	env := New()
+
+	require.Error(t, env.RenderTemplate(filepath.Join(dir, "not-exists"), dst))
+
+	src := filepath.Join(dir, "invalid.tmpl")
+	require.NoError(t, os.WriteFile(src, []byte(`{{ .A `), 0o600))
+	require.Error(t, env.RenderTemplate(src, dst))
+
+	src = filepath.Join(dir, "missing.tmpl")
+	require.NoError(t, os.WriteFile(src, []byte(`{{ .TEST_NOT_EXISTS }}`),
+		0o600))
+	require.Error(t, env.RenderTemplate(src, dst))
+	assert.NoFileExists(t, dst)
+
+	src = filepath.Join(dir, "ok.tmpl")
+	require.NoError(t, os.WriteFile(src, []byte(`ok`), 0o600))
+	require.Error(t, env.RenderTemplate(src, filepath.Join(dir, "a", "b")))
+}
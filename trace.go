@@ -0,0 +1,166 @@
+package dotenv
+
+import (
+	"encoding/json"
+	"io"
+	"log/slog"
+)
+
+// Trace events, see [TraceEvent].
+const (
+	// TraceDir is emitted for every dir, checked for .env files.
+	TraceDir = "dir"
+
+	// TraceDirChange is emitted if current dir changed since previous Load, see
+	// [Loader.WithDirChangeHook].
+	TraceDirChange = "cwd"
+
+	// TraceStop is emitted when lookup of .env files stopped.
+	TraceStop = "stop"
+
+	// TraceFile is emitted for every loaded source.
+	TraceFile = "file"
+
+	// TraceVar is emitted for every env var, defined by a loaded source.
+	TraceVar = "var"
+)
+
+// TraceEvent is an event of discovery or loading of .env files, written by
+// [Loader.WithTraceWriter] as a line of JSON.
+type TraceEvent struct {
+	// Event is a name of event, like [TraceDir], [TraceFile] and so on.
+	Event string `json:"event"`
+
+	// Dir is a checked dir for [TraceDir], a dir where lookup stopped for
+	// [TraceStop] or current dir for [TraceDirChange].
+	Dir string `json:"dir,omitempty"`
+
+	// PrevDir is current dir of previous Load for [TraceDirChange], when Dir is
+	// current dir of this Load.
+	PrevDir string `json:"prev_dir,omitempty"`
+
+	// Reason is a reason of stop for [TraceStop], see [StopReason].
+	Reason string `json:"reason,omitempty"`
+
+	// File is a name of loaded source for [TraceFile] and [TraceVar].
+	File string `json:"file,omitempty"`
+
+	// Kind is a kind of loaded source for [TraceFile], see [SourceKind].
+	Kind string `json:"kind,omitempty"`
+
+	// Key is a name of env var for [TraceVar].
+	Key string `json:"key,omitempty"`
+
+	// Line is a line of File, which defines Key, for [TraceVar].
+	Line int `json:"line,omitempty"`
+
+	// Applied is true for [TraceVar], if Key got its value from File, and false
+	// if it's overridden by a source with higher precedence.
+	Applied bool `json:"applied,omitempty"`
+
+	// Error is an error of [TraceFile], if the source can't be loaded.
+	Error string `json:"error,omitempty"`
+}
+
+// WithTraceWriter configures [Loader.Load] to write discovery and load events
+// into w, one JSON object per line, see [TraceEvent]. For instance:
+//
+//	{"event":"dir","dir":"."}
+//	{"event":"stop","dir":".","reason":"found"}
+//	{"event":"var","file":".env","key":"DATABASE_URL","line":2,"applied":true}
+//	{"event":"file","file":".env","kind":"file"}
+//
+// [TraceFile] event of a source is written after [TraceVar] events of it.
+// So editor plugins can visualize which file defines an env var. Values of env
+// vars are never written. Tracing is best effort and write errors are ignored.
+func (self *Loader) WithTraceWriter(w io.Writer) *Loader {
+	if w == nil {
+		self.trace = nil
+	} else {
+		self.trace = json.NewEncoder(w)
+	}
+	return self
+}
+
+// traceEvent writes ev, if tracing is enabled.
+func (self *Loader) traceEvent(ev TraceEvent) {
+	if self.trace != nil {
+		_ = self.trace.Encode(ev)
+	}
+}
+
+// traceDir writes [TraceDir] event.
+func (self *Loader) traceDir(dir string) {
+	if dir == "" {
+		dir = "."
+	}
+	self.traceEvent(TraceEvent{Event: TraceDir, Dir: dir})
+	self.logDebug("visit dir", slog.String("dir", dir))
+}
+
+// traceStop writes [TraceStop] event.
+func (self *Loader) traceStop() {
+	dir := self.stop.Dir
+	if dir == "" {
+		dir = "."
+	}
+	self.traceEvent(TraceEvent{
+		Event:  TraceStop,
+		Dir:    dir,
+		Reason: self.stop.Reason.String(),
+	})
+
+	if self.logEnabled() {
+		attrs := []slog.Attr{
+			slog.String("dir", dir),
+			slog.String("reason", self.stop.Reason.String()),
+		}
+		if self.stop.RootFile != "" {
+			attrs = append(attrs, slog.String("root_file", self.stop.RootFile))
+		}
+		self.logDebug("stop lookup", attrs...)
+	}
+}
+
+// traceFile writes [TraceFile] event.
+func (self *Loader) traceFile(ref SourceRef, err error) {
+	ev := TraceEvent{Event: TraceFile, File: ref.Name, Kind: ref.Kind.String()}
+	if err != nil {
+		ev.Error = err.Error()
+	}
+	self.traceEvent(ev)
+
+	if self.logEnabled() {
+		attrs := []slog.Attr{
+			slog.String("file", ev.File),
+			slog.String("kind", ev.Kind),
+		}
+		if err != nil {
+			attrs = append(attrs, slog.String("error", ev.Error))
+		}
+		self.logDebug("load source", attrs...)
+	}
+}
+
+// traceLines returns line numbers of keys of source ref for [TraceVar] events,
+// see [keyLines], or nil, if tracing is disabled or ref isn't a file.
+func (self *Loader) traceLines(ref SourceRef) map[string]int {
+	if self.trace == nil || !ref.isFile() {
+		return nil
+	}
+	return keyLines(self.fsys, ref.Name)
+}
+
+// traceVar writes [TraceVar] event.
+func (self *Loader) traceVar(fname, key string, line int, applied bool) {
+	if self.trace == nil {
+		return
+	}
+	self.traceEvent(TraceEvent{
+		Event:   TraceVar,
+		File:    fname,
+		Key:     key,
+		Line:    line,
+		Applied: applied,
+	})
+}
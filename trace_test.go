@@ -0,0 +1,84 @@
+package dotenv
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoader_WithTraceWriter(t *testing.T) {
+	changeDir(t, "testdata/g")
+	restoreEnvVars(t)
+	t.Setenv(allEnvVars[1], "env")
+
+	var buf bytes.Buffer
+	env := New()
+	assert.Same(t, env, env.WithTraceWriter(&buf))
+	require.NoError(t, env.Load())
+
+	var events []TraceEvent
+	dec := json.NewDecoder(&buf)
+	for dec.More() {
+		var ev TraceEvent
+		require.NoError(t, dec.Decode(&ev))
+		events = append(events, ev)
+	}
+
+	assert.Equal(t, []TraceEvent{
+		{Event: TraceDir, Dir: "."},
+		{Event: TraceStop, Dir: ".", Reason: "found"},
+		{
+			Event: TraceVar, File: ".env.local", Key: allEnvVars[0], Line: 1,
+			Applied: true,
+		},
+		{Event: TraceFile, File: ".env.local", Kind: "file"},
+		{Event: TraceVar, File: ".env", Key: allEnvVars[0], Line: 3},
+		{Event: TraceVar, File: ".env", Key: allEnvVars[1], Line: 2},
+		{Event: TraceFile, File: ".env", Kind: "file"},
+	}, events)
+
+	buf.Reset()
+	changeDir(t, "../a")
+	require.NoError(t, env.Load())
+	var ev TraceEvent
+	require.NoError(t, json.NewDecoder(&buf).Decode(&ev))
+	assert.Equal(t, TraceDirChange, ev.Event)
+	assert.Equal(t, "g", filepath.Base(ev.PrevDir))
+	assert.Equal(t, "a", filepath.Base(ev.Dir))
+
+	buf.Reset()
+	assert.Same(t, env, env.WithTraceWriter(nil))
+	require.NoError(t, env.Load())
+	assert.Zero(t, buf.Len())
+}
+
+func TestLoader_WithTraceWriter_values(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "values"),
+		[]byte("\nTEST_VAR1=file\n"), 0o600))
+	changeDir(t, dir)
+	restoreEnvVars(t)
+
+	var buf bytes.Buffer
+	env := New().WithRootDir(".").WithTraceWriter(&buf).
+		WithValues(map[string]string{allEnvVars[0]: "values"})
+	require.NoError(t, env.Load())
+
+	var vars []TraceEvent
+	dec := json.NewDecoder(&buf)
+	for dec.More() {
+		var ev TraceEvent
+		require.NoError(t, dec.Decode(&ev))
+		if ev.Event == TraceVar {
+			vars = append(vars, ev)
+		}
+	}
+	assert.Equal(t, []TraceEvent{
+		{Event: TraceVar, File: "values", Key: allEnvVars[0], Applied: true},
+	}, vars)
+}
@@ -0,0 +1,184 @@
+package dotenv
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// ErrInvalidConfig returned by [Loader.Validate] and [Loader.Load] if
+// configuration of [Loader] is contradictory.
+var ErrInvalidConfig = errors.New("invalid configuration")
+
+// ErrRootNotAncestor returned by [Loader.Validate] and [Loader.Load] if root dir,
+// configured by [Loader.WithRootDir], isn't current dir or any of its parents.
+// It's wrapped together with [ErrInvalidConfig]. See also
+// [Loader.WithClampRootDir].
+var ErrRootNotAncestor = errors.New("root dir isn't an ancestor of current dir")
+
+// WithClampRootDir configures [Loader] to clamp root dir, configured by
+// [Loader.WithRootDir], to the nearest common ancestor of it and current dir,
+// if root dir isn't current dir or any of its parents. Without it
+// [Loader.Load] returns an error wrapping [ErrRootNotAncestor]. Either way
+// lookup never silently walks up to "/".
+func (self *Loader) WithClampRootDir() *Loader {
+	self.clampRootDir = true
+	return self
+}
+
+// Validate checks configuration of [Loader] and returns an error, if it's
+// contradictory, like:
+//
+//   - Name of environment contains a path separator.
+//   - Any of root files is empty, contains a path separator or is a malformed
+//     glob pattern.
+//   - Any of .env files of [Loader.WithFiles] or [Loader.WithFileOrder] is
+//     empty or contains a path separator.
+//   - Base name of .env files or ignore file contains a path separator.
+//   - Unknown preset.
+//   - Name of namespace is empty or contains a path separator.
+//   - Fallback start dir isn't absolute.
+//   - Schema of [Loader.WithDisallowUnknown] isn't a struct.
+//   - Archive of [Loader.WithArchiveSource] can't be read.
+//   - Root dir, configured by [Loader.WithRootDir], isn't current dir or any of
+//     its parents, so lookup never stops at it. The error wraps
+//     [ErrRootNotAncestor]. See also [Loader.WithClampRootDir].
+//
+// Returned error wraps [ErrInvalidConfig] and contains all found problems. It
+// doesn't touch any files and [Loader.Load] calls it before lookup.
+func (self *Loader) Validate() error {
+	var errs []error
+	if strings.ContainsFunc(self.envSuffix, isPathSeparator) {
+		errs = append(errs, fmt.Errorf(
+			"name of environment %q contains a path separator", self.envSuffix))
+	}
+
+	for _, fname := range self.rootFiles {
+		if fname == "" || strings.ContainsFunc(fname, isPathSeparator) {
+			errs = append(errs, fmt.Errorf(
+				"root file %q must be a non empty name without path separators",
+				fname))
+		} else if _, err := filepath.Match(fname, ""); err != nil {
+			errs = append(errs, fmt.Errorf("root file %q: %w", fname, err))
+		}
+	}
+
+	var customFiles []string
+	if self.files != nil || self.fileOrder != nil {
+		customFiles = self.valueFiles()
+	}
+
+	for _, fname := range customFiles {
+		if fname == "" || strings.ContainsFunc(fname, isPathSeparator) {
+			errs = append(errs, fmt.Errorf(
+				".env file %q must be a non empty name without path separators",
+				fname))
+		}
+	}
+
+	if strings.ContainsFunc(self.fileName, isPathSeparator) {
+		errs = append(errs, fmt.Errorf(
+			"file name %q contains a path separator", self.fileName))
+	}
+
+	if strings.ContainsFunc(self.ignoreFile, isPathSeparator) {
+		errs = append(errs, fmt.Errorf(
+			"ignore file %q contains a path separator", self.ignoreFile))
+	}
+
+	if self.preset < PresetDefault || self.preset > PresetHeroku {
+		errs = append(errs, fmt.Errorf("unknown preset %v", int(self.preset)))
+	}
+
+	if self.namespaced && (self.namespace == "" ||
+		strings.ContainsFunc(self.namespace, isPathSeparator)) {
+		errs = append(errs, fmt.Errorf(
+			"namespace %q must be a non empty name without path separators",
+			self.namespace))
+	}
+
+	if self.fallbackStartDir != "" && !filepath.IsAbs(self.fallbackStartDir) {
+		errs = append(errs, fmt.Errorf(
+			"fallback start dir %q isn't absolute", self.fallbackStartDir))
+	}
+
+	if self.schemaErr != nil {
+		errs = append(errs, self.schemaErr)
+	}
+	if self.archiveErr != nil {
+		errs = append(errs, self.archiveErr)
+	}
+
+	if err := self.validateRootDir(); err != nil {
+		errs = append(errs, err)
+	}
+
+	if len(errs) == 0 {
+		return nil
+	}
+	return fmt.Errorf("%w: %w", ErrInvalidConfig, errors.Join(errs...))
+}
+
+// validateRootDir checks configured root dir is current dir or any of its
+// parents. It does nothing if current dir is unknown, because lookup will fail
+// anyway.
+func (self *Loader) validateRootDir() error {
+	if self.rootDir == string(filepath.Separator) || self.clampRootDir {
+		return nil
+	}
+
+	curDir := self.startAt
+	if curDir == "" && self.fsys != nil {
+		curDir = string(filepath.Separator)
+	} else if curDir == "" {
+		dir, err := os.Getwd()
+		if err != nil {
+			return nil //nolint:nilerr // lookup will report it
+		}
+		curDir = dir
+	}
+
+	if !isAncestor(self.rootDir, curDir) {
+		return fmt.Errorf("%w: root dir %q, current dir %q", ErrRootNotAncestor,
+			self.rootDir, curDir)
+	}
+	return nil
+}
+
+// walkRootDir returns root dir for lookup, which starts at startDir: configured
+// root dir or, if it's configured by [Loader.WithClampRootDir], the nearest
+// common ancestor of it and startDir.
+func (self *Loader) walkRootDir(startDir string) string {
+	if !self.clampRootDir || self.rootDir == string(filepath.Separator) {
+		return self.rootDir
+	} else if startDir == "" {
+		dir, err := os.Getwd()
+		if err != nil {
+			return self.rootDir
+		}
+		startDir = dir
+	}
+
+	rootDir := self.rootDir
+	for !isAncestor(rootDir, startDir) {
+		parent := filepath.Dir(rootDir)
+		if parent == rootDir {
+			break
+		}
+		rootDir = parent
+	}
+	return rootDir
+}
+
+// isAncestor returns true if dir is path or any of its parents.
+func isAncestor(dir, path string) bool {
+	rel, err := filepath.Rel(dir, path)
+	return err == nil && rel != ".." &&
+		!strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
+
+func isPathSeparator(r rune) bool {
+	return r < 0x80 && os.IsPathSeparator(uint8(r))
+}
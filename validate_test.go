@@ -0,0 +1,116 @@
+package dotenv
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoader_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		cfg     func(env *Loader)
+		wantErr bool
+	}{
+		{
+			name: "default",
+			cfg:  func(env *Loader) {},
+		},
+		{
+			name: "valid",
+			cfg: func(env *Loader) {
+				env.WithEnvSuffix("test").WithRootFiles(".git").WithRootDir("..").
+					WithPreset(PresetVite)
+			},
+		},
+		{
+			name:    "env suffix with separator",
+			cfg:     func(env *Loader) { env.WithEnvSuffix("../test") },
+			wantErr: true,
+		},
+		{
+			name:    "empty root file",
+			cfg:     func(env *Loader) { env.WithRootFiles("go.mod", "") },
+			wantErr: true,
+		},
+		{
+			name:    "root file with separator",
+			cfg:     func(env *Loader) { env.WithRootFiles("a/go.mod") },
+			wantErr: true,
+		},
+		{
+			name:    "malformed root file pattern",
+			cfg:     func(env *Loader) { env.WithRootFiles("*.workspace", "[a") },
+			wantErr: true,
+		},
+		{
+			name:    "ignore file with separator",
+			cfg:     func(env *Loader) { env.WithIgnoreFile("a/.dotenvignore") },
+			wantErr: true,
+		},
+		{
+			name:    "unknown preset",
+			cfg:     func(env *Loader) { env.WithPreset(Preset(100)) },
+			wantErr: true,
+		},
+		{
+			name:    "relative fallback start dir",
+			cfg:     func(env *Loader) { env.fallbackStartDir = "testdata" },
+			wantErr: true,
+		},
+		{
+			name:    "root dir isn't an ancestor",
+			cfg:     func(env *Loader) { env.WithRootDir("testdata") },
+			wantErr: true,
+		},
+		{
+			name:    "root dir is a sibling",
+			cfg:     func(env *Loader) { env.WithRootDir("../expx-dotenv-sibling") },
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			env := New()
+			tt.cfg(env)
+			if tt.wantErr {
+				require.ErrorIs(t, env.Validate(), ErrInvalidConfig)
+				require.ErrorIs(t, env.Load(), ErrInvalidConfig)
+			} else {
+				require.NoError(t, env.Validate())
+			}
+		})
+	}
+}
+
+func TestLoader_WithClampRootDir(t *testing.T) {
+	curDir := valueNoError[string](t)(os.Getwd())
+	testdata := filepath.Join(curDir, "testdata")
+	changeDir(t, "testdata/e/f")
+
+	env := New().WithRootDir(filepath.Join(testdata, "g"))
+	require.ErrorIs(t, env.Validate(), ErrRootNotAncestor)
+	require.ErrorIs(t, env.Validate(), ErrInvalidConfig)
+
+	assert.Same(t, env, env.WithClampRootDir())
+	require.NoError(t, env.Validate())
+
+	var dirs []string
+	for dir, err := range env.Dirs() {
+		require.NoError(t, err)
+		dirs = append(dirs, dir)
+	}
+	assert.Equal(t, []string{
+		filepath.Join(testdata, "e", "f"),
+		filepath.Join(testdata, "e"),
+		testdata,
+	}, dirs)
+	assert.Equal(t, Stop{Reason: StopRootDir, Dir: testdata}, env.stop)
+
+	env.WithRootDir("..")
+	assert.Equal(t, filepath.Join(testdata, "e"), env.walkRootDir(""))
+}
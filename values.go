@@ -0,0 +1,27 @@
+package dotenv
+
+import (
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"strings"
+)
+
+// decodeValue decodes s if it has "base64:" or "hex:" prefix and returns s as
+// is otherwise.
+func decodeValue(s string) (string, error) {
+	if b64, ok := strings.CutPrefix(s, "base64:"); ok {
+		b, err := base64.StdEncoding.DecodeString(b64)
+		if err != nil {
+			return "", fmt.Errorf("decode base64 value: %w", err)
+		}
+		return string(b), nil
+	} else if h, ok := strings.CutPrefix(s, "hex:"); ok {
+		b, err := hex.DecodeString(h)
+		if err != nil {
+			return "", fmt.Errorf("decode hex value: %w", err)
+		}
+		return string(b), nil
+	}
+	return s, nil
+}
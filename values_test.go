@@ -0,0 +1,60 @@
+package dotenv
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDecodeValue(t *testing.T) {
+	tests := []struct {
+		name    string
+		value   string
+		expect  string
+		wantErr bool
+	}{
+		{
+			name:   "plain",
+			value:  "hello",
+			expect: "hello",
+		},
+		{
+			name:   "base64",
+			value:  "base64:aGVsbG8=",
+			expect: "hello",
+		},
+		{
+			name:   "hex",
+			value:  "hex:68656c6c6f",
+			expect: "hello",
+		},
+		{
+			name:   "prefix in the middle",
+			value:  "abc base64:aGVsbG8=",
+			expect: "abc base64:aGVsbG8=",
+		},
+		{
+			name:    "invalid base64",
+			value:   "base64:!",
+			wantErr: true,
+		},
+		{
+			name:    "invalid hex",
+			value:   "hex:zz",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := decodeValue(tt.value)
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expect, got)
+		})
+	}
+}
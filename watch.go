@@ -0,0 +1,324 @@
+package dotenv
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"path/filepath"
+	"slices"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+// WatchDelay is a delay of reload by [Loader.Watch] after the last change of
+// .env files, so a burst of events, like editors save files, causes one reload.
+const WatchDelay = 100 * time.Millisecond
+
+// WithPollInterval configures [Loader.Watch] to poll modification time and
+// size of watched .env files every d, alongside of fsnotify watcher. Events of
+// fsnotify are unreliable on NFS and some container filesystems, so polling
+// notices changes there. If fsnotify watcher can't be created or
+// [Loader.WithFS] is configured, Watch uses polling only. Polling is disabled
+// by default.
+func (self *Loader) WithPollInterval(d time.Duration) *Loader {
+	self.pollInterval = d
+	return self
+}
+
+// WithReloadHook configures [Loader.Watch] to call fn after every reload,
+// including reloads, which changed nothing, before onChange of Watch. It may be
+// used for exporting metrics of reloads, so operators can alert when a watcher
+// starts failing silently.
+func (self *Loader) WithReloadHook(fn func(changes Changes)) *Loader {
+	self.reloadHook = fn
+	return self
+}
+
+// Clock implements timers of [Loader.Watch]. See [WithClock].
+type Clock interface {
+	// After waits for d and then sends current time on returned channel, see
+	// [time.After].
+	After(d time.Duration) <-chan time.Time
+}
+
+type stdClock struct{}
+
+func (self stdClock) After(d time.Duration) <-chan time.Time {
+	return time.After(d)
+}
+
+// WithClock configures [Loader] with custom implementation of [Clock]
+// interface, instead of real time, so tests can drive polling and delay of
+// reload of [Loader.Watch] without sleeps.
+func WithClock(c Clock) Option { return func(l *Loader) { l.clock = c } }
+
+// Changes describes a reload of .env files by [Loader.Watch].
+type Changes struct {
+	// Keys contains changed env vars, sorted by key.
+	Keys []KeyDiff
+
+	// Err is an error of reload. If it isn't nil, previous values of env vars
+	// are kept.
+	Err error
+}
+
+// Watch loads .env files like [Loader.Load] does, watches dirs of resolved
+// .env files and reloads them, when any of them changes, so long-running
+// services get hot-reloadable configuration without restart. After every
+// reload, which changed any env var or failed, it calls onChange with added,
+// changed and removed keys:
+//
+//	err := env.Watch(ctx, func(changes dotenv.Changes) error {
+//		if changes.Err != nil {
+//			log.Printf("reload .env files: %v", changes.Err)
+//			return nil
+//		}
+//		for _, d := range changes.Keys {
+//			log.Printf("%v %v", d.Kind, d.Key)
+//		}
+//		return nil
+//	})
+//
+// Reload re-applies env vars, which were applied by previous load, so .env
+// files can change them. Env vars, which came from the process environment,
+// still win, unless [Loader.WithOverride] is configured. Only changed env vars
+// are set or unset, and env vars, which aren't defined by .env files anymore,
+// get back their values of the process environment.
+//
+// It blocks until ctx is done, watching fails or onChange returns an error, and
+// returns this error. It reloads [WatchDelay] after the last change. With
+// [Loader.WithFS] it requires [Loader.WithPollInterval] and polls only.
+func (self *Loader) Watch(ctx context.Context,
+	onChange func(changes Changes) error,
+) error {
+	if self.fsys != nil && self.pollInterval <= 0 {
+		return errors.New("watch: can't watch fs.FS without polling")
+	}
+
+	origins := environMap(self.procEnv.Environ())
+	if err := self.Load(); err != nil {
+		return err
+	}
+	applied := self.appliedKeys()
+	maps.DeleteFunc(origins, func(k, v string) bool {
+		return !slices.Contains(applied, k)
+	})
+
+	var events <-chan fsnotify.Event
+	var errs <-chan error
+	var watcher *fsnotify.Watcher
+	if self.fsys == nil {
+		w, err := fsnotify.NewWatcher()
+		if err == nil {
+			defer w.Close()
+			if err := self.watchDirs(w); err != nil {
+				return err
+			}
+			watcher, events, errs = w, w.Events, w.Errors
+		} else if self.pollInterval <= 0 {
+			return fmt.Errorf("watch: %w", err)
+		}
+	}
+
+	var poll, delay <-chan time.Time
+	var stamps map[string]fileStamp
+	if self.pollInterval > 0 {
+		poll, stamps = self.clock.After(self.pollInterval), self.pollStamps()
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("watch: %w", ctx.Err())
+		case err := <-errs:
+			return fmt.Errorf("watch: %w", err)
+		case event := <-events:
+			if self.watchedFile(event.Name) {
+				delay = self.clock.After(WatchDelay)
+			}
+		case <-poll:
+			poll = self.clock.After(self.pollInterval)
+			if next := self.pollStamps(); !maps.Equal(stamps, next) {
+				stamps = next
+				delay = self.clock.After(WatchDelay)
+			}
+		case <-delay:
+			delay = nil
+			changes := self.reload(origins)
+			if self.reloadHook != nil {
+				self.reloadHook(changes)
+			}
+			if changes.Err == nil && len(changes.Keys) == 0 {
+				continue
+			} else if err := onChange(changes); err != nil {
+				return err
+			} else if err := self.watchDirs(watcher); err != nil {
+				return err
+			} else if poll != nil {
+				stamps = self.pollStamps()
+			}
+		}
+	}
+}
+
+// watchDirs adds dirs of resolved .env files to watcher. It does nothing if
+// watcher is nil, because Watch polls only.
+func (self *Loader) watchDirs(watcher *fsnotify.Watcher) error {
+	if watcher == nil {
+		return nil
+	}
+
+	for _, dir := range self.watchedDirs() {
+		if err := watcher.Add(dir); err != nil {
+			return fmt.Errorf("watch %v: %w", dir, err)
+		}
+	}
+	return nil
+}
+
+// watchedDirs returns sorted dirs of resolved .env files or start dir, if no
+// .env files were found.
+func (self *Loader) watchedDirs() []string {
+	var dirs []string
+	for _, ref := range self.precedence {
+		if ref.Kind == SourceFile || ref.Kind == SourceSnapshot {
+			dirs = append(dirs, filepath.Dir(ref.Name))
+		}
+	}
+	if len(dirs) == 0 {
+		dirs = append(dirs, self.envDir)
+	}
+
+	for i, dir := range dirs {
+		if dir == "" {
+			dirs[i] = "."
+		}
+	}
+	return slices.Compact(slices.Sorted(slices.Values(dirs)))
+}
+
+// watchedFile returns true if fname is any of .env files, which can change
+// result of load.
+func (self *Loader) watchedFile(fname string) bool {
+	base := filepath.Base(fname)
+	if slices.Contains(self.envFiles(), base) {
+		return true
+	}
+	return slices.ContainsFunc(self.precedence, func(ref SourceRef) bool {
+		return ref.Name != "" && filepath.Base(ref.Name) == base
+	})
+}
+
+// fileStamp is modification time and size of a polled .env file.
+type fileStamp struct {
+	modTime time.Time
+	size    int64
+}
+
+// pollStamps returns stamps of existing .env files in watched dirs, which can
+// change result of load.
+func (self *Loader) pollStamps() map[string]fileStamp {
+	names := slices.Clone(self.envFiles())
+	for _, ref := range self.precedence {
+		if ref.Name != "" {
+			names = append(names, filepath.Base(ref.Name))
+		}
+	}
+	names = slices.Compact(slices.Sorted(slices.Values(names)))
+
+	stamps := make(map[string]fileStamp)
+	for _, dir := range self.watchedDirs() {
+		for _, name := range names {
+			fname := filepath.Join(dir, name)
+			if fi, err := self.filer.Stat(fname); err == nil {
+				stamps[fname] = fileStamp{modTime: fi.ModTime(), size: fi.Size()}
+			}
+		}
+	}
+	return stamps
+}
+
+// reload loads .env files again and returns changed env vars. It loads them by
+// a copy of [Loader], which sees the process environment without applied env
+// vars, and applies changed env vars only, so readers never see unchanged env
+// vars missing. If reload failed, nothing changes.
+//
+// origins contains values of env vars of the process environment, which were
+// replaced by applied env vars. Applied env vars, missing in it, weren't
+// defined. It's updated by applied changes.
+func (self *Loader) reload(origins map[string]string) Changes {
+	base := mapProcessEnv(environMap(self.procEnv.Environ()))
+	prevApplied := self.appliedKeys()
+	if !self.noSetenv {
+		for _, k := range prevApplied {
+			if v, ok := origins[k]; ok {
+				base[k] = v
+			} else {
+				delete(base, k)
+			}
+		}
+	}
+
+	l := *self
+	l.procEnv, l.setenvFn = base, base.Setenv
+	if err := l.Load(); err != nil {
+		return Changes{Err: err}
+	}
+
+	prevLoaded := self.loaded
+	l.procEnv, l.setenvFn = self.procEnv, self.setenvFn
+	setenvMu.Lock()
+	*self = l
+	setenvMu.Unlock()
+	if !self.noSetenv {
+		self.applyReload(prevApplied, origins)
+	}
+	return Changes{Keys: diffVars(prevLoaded, self.loaded).Keys}
+}
+
+// applyReload sets env vars, applied by reload, which values changed, and
+// restores values from origins of env vars prevApplied, which aren't applied
+// anymore.
+func (self *Loader) applyReload(prevApplied []string,
+	origins map[string]string,
+) {
+	setenvMu.Lock()
+	defer setenvMu.Unlock()
+
+	applied := self.appliedKeys()
+	for _, k := range applied {
+		cur, ok := self.procEnv.LookupEnv(k)
+		if ok && !slices.Contains(prevApplied, k) {
+			origins[k] = cur
+		}
+		if v := self.loaded[k]; !ok || cur != v {
+			self.setenv(self.winners[k].String(), k, v)
+		}
+	}
+	self.vars = self.pending
+
+	for _, k := range prevApplied {
+		if slices.Contains(applied, k) {
+			continue
+		} else if v, ok := origins[k]; ok {
+			_ = self.procEnv.Setenv(k, v)
+			delete(origins, k)
+		} else {
+			_ = self.procEnv.Unsetenv(k)
+		}
+	}
+}
+
+// appliedKeys returns env vars, applied by last load, sorted by key.
+func (self *Loader) appliedKeys() []string {
+	var keys []string
+	for k, ref := range self.winners {
+		if ref.Kind != SourceEnv {
+			keys = append(keys, k)
+		}
+	}
+	slices.Sort(keys)
+	return keys
+}
@@ -0,0 +1,265 @@
+package dotenv
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoader_Watch(t *testing.T) {
+	dir := t.TempDir()
+	fname := filepath.Join(dir, ".env")
+	require.NoError(t, os.WriteFile(fname,
+		[]byte("TEST_VAR1=first\nTEST_VAR2=first\n"), 0o600))
+	changeDir(t, dir)
+	restoreEnvVars(t)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	loaded := make(chan struct{})
+	var once sync.Once
+	env := New().WithRootDir(".").WithMiddleware(func(next LoadFunc) LoadFunc {
+		return func() error {
+			err := next()
+			once.Do(func() { close(loaded) })
+			return err
+		}
+	})
+
+	changes := make(chan Changes)
+	watchErr := make(chan error, 1)
+	go func() {
+		watchErr <- env.Watch(ctx, func(c Changes) error {
+			changes <- c
+			return nil
+		})
+	}()
+	<-loaded
+
+	waitChanges := func(content string) Changes {
+		t.Helper()
+		// Watch can start watching after the first write, so write until it
+		// notices.
+		for {
+			require.NoError(t, os.WriteFile(fname, []byte(content), 0o600))
+			select {
+			case c := <-changes:
+				return c
+			case err := <-watchErr:
+				require.NoError(t, err)
+			case <-time.After(500 * time.Millisecond):
+			}
+		}
+	}
+
+	c := waitChanges("TEST_VAR1=second\nTEST_VAR3=new\n")
+	require.NoError(t, c.Err)
+	assert.Equal(t, []KeyDiff{
+		{Key: allEnvVars[0], Kind: DiffChanged, Old: "first", New: "second"},
+		{Key: allEnvVars[1], Kind: DiffRemoved, Old: "first"},
+		{Key: "TEST_VAR3", Kind: DiffAdded, New: "new"},
+	}, c.Keys)
+	assert.Equal(t, "second", os.Getenv(allEnvVars[0]))
+	assert.Empty(t, os.Getenv(allEnvVars[1]))
+	assert.Equal(t, "new", os.Getenv("TEST_VAR3"))
+	t.Cleanup(func() { os.Unsetenv("TEST_VAR3") })
+
+	c = waitChanges("TEST_VAR1='broken\n")
+	require.Error(t, c.Err)
+	assert.Equal(t, "second", os.Getenv(allEnvVars[0]))
+	assert.Equal(t, "new", os.Getenv("TEST_VAR3"))
+
+	cancel()
+	require.ErrorIs(t, <-watchErr, context.Canceled)
+}
+
+func TestLoader_Watch_onChangeError(t *testing.T) {
+	dir := t.TempDir()
+	fname := filepath.Join(dir, ".env")
+	require.NoError(t, os.WriteFile(fname, []byte("TEST_VAR1=first\n"), 0o600))
+	changeDir(t, dir)
+	restoreEnvVars(t)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	errStop := errors.New("stop")
+	watchErr := make(chan error, 1)
+	go func() {
+		watchErr <- New().WithRootDir(".").Watch(ctx,
+			func(c Changes) error { return errStop })
+	}()
+
+	for i := 0; ; i++ {
+		require.NoError(t, os.WriteFile(fname,
+			[]byte("TEST_VAR1=changed"+string(rune('a'+i%26))+"\n"), 0o600))
+		select {
+		case err := <-watchErr:
+			require.ErrorIs(t, err, errStop)
+			return
+		case <-time.After(500 * time.Millisecond):
+		}
+	}
+}
+
+func TestLoader_Watch_errors(t *testing.T) {
+	ctx := context.Background()
+	noop := func(Changes) error { return nil }
+	require.Error(t, New().WithFS(fstest.MapFS{}).Watch(ctx, noop))
+	require.ErrorIs(t, New().WithEnvSuffix("a/b").Watch(ctx, noop),
+		ErrInvalidConfig)
+}
+
+func TestLoader_pollStamps(t *testing.T) {
+	dir := t.TempDir()
+	fname := filepath.Join(dir, ".env")
+	require.NoError(t, os.WriteFile(fname, []byte("TEST_VAR1=first\n"), 0o600))
+	changeDir(t, dir)
+	restoreEnvVars(t)
+
+	env := New().WithRootDir(".").WithPollInterval(time.Second)
+	require.NoError(t, env.Load())
+	assert.Equal(t, time.Second, env.pollInterval)
+
+	stamps := env.pollStamps()
+	require.Len(t, stamps, 1)
+	assert.Contains(t, stamps, ".env")
+	assert.Equal(t, stamps, env.pollStamps())
+
+	require.NoError(t, os.WriteFile(fname, []byte("TEST_VAR1=second\n"), 0o600))
+	assert.NotEqual(t, stamps, env.pollStamps())
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, ".env.local"),
+		[]byte("TEST_VAR1=local\n"), 0o600))
+	assert.Len(t, env.pollStamps(), 2)
+}
+
+type recordProcessEnv struct {
+	mapProcessEnv
+	calls []string
+}
+
+func (self *recordProcessEnv) Setenv(key, value string) error {
+	self.calls = append(self.calls, "set "+key)
+	return self.mapProcessEnv.Setenv(key, value)
+}
+
+func (self *recordProcessEnv) Unsetenv(key string) error {
+	self.calls = append(self.calls, "unset "+key)
+	return self.mapProcessEnv.Unsetenv(key)
+}
+
+func TestLoader_reload(t *testing.T) {
+	tests := []struct {
+		name     string
+		override bool
+		calls    []string
+	}{
+		{
+			name:  "without override",
+			calls: []string{"set TEST_VAR2", "unset TEST_VAR3"},
+		},
+		{
+			name:     "with override",
+			override: true,
+			calls:    []string{"set TEST_VAR2", "set TEST_VAR1", "unset TEST_VAR3"},
+		},
+	}
+
+	environ := mapProcessEnv{
+		"TEST_VAR1": "process",
+		"TEST_VAR2": "two",
+		"TEST_VAR4": "same",
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			fname := filepath.Join(dir, ".env")
+			require.NoError(t, os.WriteFile(fname, []byte(
+				"TEST_VAR1=one\nTEST_VAR2=one\nTEST_VAR3=one\nTEST_VAR4=same\n"),
+				0o600))
+			changeDir(t, dir)
+
+			procEnv := &recordProcessEnv{
+				mapProcessEnv: mapProcessEnv{"TEST_VAR1": "process"},
+			}
+			env := New(WithProcessEnv(procEnv)).WithRootDir(".")
+			env.override = tt.override
+			origins := map[string]string{}
+			if tt.override {
+				origins[allEnvVars[0]] = "process"
+			}
+			require.NoError(t, env.Load())
+
+			require.NoError(t, os.WriteFile(fname, []byte(
+				"TEST_VAR2=two\nTEST_VAR4=same\n"), 0o600))
+			procEnv.calls = nil
+			changes := env.reload(origins)
+			require.NoError(t, changes.Err)
+			assert.NotEmpty(t, changes.Keys)
+			assert.Equal(t, tt.calls, procEnv.calls)
+			assert.Equal(t, environ, procEnv.mapProcessEnv)
+			assert.Empty(t, origins)
+
+			require.NoError(t, os.WriteFile(fname, []byte("TEST_VAR2='broken\n"),
+				0o600))
+			procEnv.calls = nil
+			require.Error(t, env.reload(origins).Err)
+			assert.Empty(t, procEnv.calls)
+			assert.Equal(t, environ, procEnv.mapProcessEnv)
+		})
+	}
+}
+
+func TestLoader_WithReloadHook(t *testing.T) {
+	dir := t.TempDir()
+	fname := filepath.Join(dir, ".env")
+	require.NoError(t, os.WriteFile(fname, []byte("TEST_VAR1=first\n"), 0o600))
+	changeDir(t, dir)
+	restoreEnvVars(t)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	reloads := make(chan Changes)
+	watchErr := make(chan error, 1)
+	go func() {
+		watchErr <- New().WithRootDir(".").
+			WithReloadHook(func(c Changes) {
+				select {
+				case reloads <- c:
+				case <-ctx.Done():
+				}
+			}).
+			Watch(ctx, func(c Changes) error {
+				return errors.New("unexpected onChange")
+			})
+	}()
+
+	// Rewrite the same content, which changes nothing, until Watch notices.
+	for {
+		require.NoError(t, os.WriteFile(fname, []byte("TEST_VAR1=first\n"),
+			0o600))
+		select {
+		case c := <-reloads:
+			require.NoError(t, c.Err)
+			assert.Empty(t, c.Keys)
+			cancel()
+			require.ErrorIs(t, <-watchErr, context.Canceled)
+			return
+		case err := <-watchErr:
+			require.NoError(t, err)
+		case <-time.After(500 * time.Millisecond):
+		}
+	}
+}